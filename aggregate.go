@@ -0,0 +1,208 @@
+package sqlorm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common"
+	"gorm.io/gorm"
+)
+
+const (
+	AggCount = "COUNT"
+	AggSum   = "SUM"
+	AggAvg   = "AVG"
+	AggMin   = "MIN"
+	AggMax   = "MAX"
+)
+
+var havingOperators = map[string]bool{
+	"=":  true,
+	"<>": true,
+	"!=": true,
+	">":  true,
+	">=": true,
+	"<":  true,
+	"<=": true,
+}
+
+type Aggregate struct {
+	Func   string
+	Column string
+	Alias  string
+}
+
+type HavingCondition struct {
+	Func     string
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+type GroupOptions struct {
+	Columns     []string
+	Aggregates  []Aggregate
+	Having      []HavingCondition
+	Order       []string
+	WithDeleted bool
+	OnlyDeleted bool
+}
+
+// Sum returns the total of column scanned as T. Use an integer T for integer
+// columns, or string or a decimal type implementing sql.Scanner for numeric
+// columns that must not lose precision through float64, e.g.
+// sqlorm.Sum[string](repo, nil, "amount"). It returns the zero T when no row
+// matches.
+func Sum[T any, M any](repo *Repository[M], where Query, column string, options ...FindOneOptions) (T, error) {
+	return aggregateAs[T](repo, where, AggSum, column, options...)
+}
+
+// Avg is Sum for the average value.
+func Avg[T any, M any](repo *Repository[M], where Query, column string, options ...FindOneOptions) (T, error) {
+	return aggregateAs[T](repo, where, AggAvg, column, options...)
+}
+
+// Min returns the smallest value of column scanned as T, so timestamps,
+// strings and large integers keep their type, e.g.
+// sqlorm.Min[time.Time](repo, nil, "created_at"). It returns the zero T when
+// no row matches.
+func Min[T any, M any](repo *Repository[M], where Query, column string, options ...FindOneOptions) (T, error) {
+	return aggregateAs[T](repo, where, AggMin, column, options...)
+}
+
+// Max is Min for the largest value.
+func Max[T any, M any](repo *Repository[M], where Query, column string, options ...FindOneOptions) (T, error) {
+	return aggregateAs[T](repo, where, AggMax, column, options...)
+}
+
+func aggregateAs[T any, M any](repo *Repository[M], where Query, fn string, column string, options ...FindOneOptions) (T, error) {
+	var value sql.Null[T]
+	tx, err := repo.aggregateQuery(where, fn, column, options...)
+	if err != nil {
+		return value.V, err
+	}
+	rows, err := tx.Rows()
+	if err != nil {
+		return value.V, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&value); err != nil {
+			return value.V, err
+		}
+	}
+	return value.V, rows.Err()
+}
+
+func (repo *Repository[M]) aggregateQuery(where Query, fn string, column string, options ...FindOneOptions) (*gorm.DB, error) {
+	var model M
+
+	var opt FindOneOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+	if opt.Lock != nil {
+		return nil, ErrLockAggregate
+	}
+
+	tx := repo.DB.Model(&model)
+	expr, err := aggregateExpr(tx, fn, column)
+	if err != nil {
+		return nil, err
+	}
+	if opt.WithDeleted {
+		tx = tx.Unscoped()
+	}
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
+	}
+
+	tx = repo.applyQuery(tx, where)
+	return tx.Select(expr), nil
+}
+
+// GroupBy runs an aggregate query grouped by opt.Columns and scans the rows
+// into dest, which must be a pointer to a slice of structs whose fields match
+// the grouped columns and the aggregate aliases.
+func (repo *Repository[M]) GroupBy(where Query, opt GroupOptions, dest interface{}) error {
+	var model M
+	tx := repo.DB.Model(&model)
+
+	var selects []string
+	for _, column := range opt.Columns {
+		if !isValidColumn(column) {
+			return fmt.Errorf("%w %q in group by", ErrInvalidColumn, column)
+		}
+		selects = append(selects, tx.Statement.Quote(column))
+	}
+	for _, agg := range opt.Aggregates {
+		expr, err := aggregateExpr(tx, agg.Func, agg.Column)
+		if err != nil {
+			return err
+		}
+		if agg.Alias != "" {
+			if !isValidColumn(agg.Alias) || strings.Contains(agg.Alias, ".") {
+				return fmt.Errorf("%w %q as aggregate alias", ErrInvalidColumn, agg.Alias)
+			}
+			expr += " AS " + tx.Statement.Quote(agg.Alias)
+		}
+		selects = append(selects, expr)
+	}
+	if len(selects) == 0 {
+		return fmt.Errorf("sqlorm: group by requires columns or aggregates")
+	}
+	tx = tx.Select(strings.Join(selects, ", "))
+
+	for _, column := range opt.Columns {
+		tx = tx.Group(tx.Statement.Quote(column))
+	}
+	for _, having := range opt.Having {
+		expr, err := aggregateExpr(tx, having.Func, having.Column)
+		if err != nil {
+			return err
+		}
+		if !havingOperators[having.Operator] {
+			return fmt.Errorf("sqlorm: invalid having operator %q", having.Operator)
+		}
+		tx = tx.Having(expr+" "+having.Operator+" ?", having.Value)
+	}
+	for _, order := range opt.Order {
+		// Aggregate aliases are plain identifiers, so they pass as columns.
+		if err := validateOrder(order); err != nil {
+			return err
+		}
+		tx = tx.Order(order)
+	}
+	if opt.WithDeleted {
+		tx = tx.Unscoped()
+	}
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
+	}
+
+	tx = repo.applyQuery(tx, where)
+
+	result := tx.Scan(dest)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func aggregateExpr(tx *gorm.DB, fn string, column string) (string, error) {
+	fn = strings.ToUpper(fn)
+	switch fn {
+	case AggCount, AggSum, AggAvg, AggMin, AggMax:
+	default:
+		return "", fmt.Errorf("sqlorm: invalid aggregate function %q", fn)
+	}
+	if column == "*" && fn == AggCount {
+		return "COUNT(*)", nil
+	}
+	if !isValidColumn(column) {
+		return "", fmt.Errorf("%w %q in aggregate", ErrInvalidColumn, column)
+	}
+	return fn + "(" + tx.Statement.Quote(column) + ")", nil
+}
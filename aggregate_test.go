@@ -0,0 +1,150 @@
+package sqlorm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+func Test_Aggregate(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type Invoice struct {
+		gorm.Model
+		Status   string  `gorm:"type:varchar(50)"`
+		Amount   float64 `gorm:"type:numeric"`
+		Quantity int64
+	}
+	err := db.AutoMigrate(&Invoice{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[Invoice]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*Invoice{
+		{Status: "paid", Amount: 10, Quantity: 1},
+		{Status: "paid", Amount: 30, Quantity: 2},
+		{Status: "open", Amount: 5, Quantity: 3},
+		{Status: "open", Amount: 1, Quantity: 4},
+		{Status: "void", Amount: 100, Quantity: 5},
+	}, 10)
+	require.Nil(t, err)
+
+	sum, err := sqlorm.Sum[float64](&repo, nil, "amount")
+	require.Nil(t, err)
+	require.Equal(t, float64(146), sum)
+
+	sum, err = sqlorm.Sum[float64](&repo, map[string]any{"status": "paid"}, "amount")
+	require.Nil(t, err)
+	require.Equal(t, float64(40), sum)
+
+	exact, err := sqlorm.Sum[string](&repo, nil, "amount")
+	require.Nil(t, err)
+	require.Equal(t, "146", exact)
+
+	quantity, err := sqlorm.Sum[int64](&repo, map[string]any{"status": "paid"}, "quantity")
+	require.Nil(t, err)
+	require.Equal(t, int64(3), quantity)
+
+	avg, err := sqlorm.Avg[float64](&repo, func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("status", "open")
+	}, "amount")
+	require.Nil(t, err)
+	require.Equal(t, float64(3), avg)
+
+	minimum, err := sqlorm.Min[float64](&repo, nil, "amount")
+	require.Nil(t, err)
+	require.Equal(t, float64(1), minimum)
+
+	maximum, err := sqlorm.Max[float64](&repo, map[string]any{"status": "open"}, "amount")
+	require.Nil(t, err)
+	require.Equal(t, float64(5), maximum)
+
+	// Typed results for non numeric columns
+	first, err := sqlorm.Min[time.Time](&repo, nil, "created_at")
+	require.Nil(t, err)
+	require.False(t, first.IsZero())
+
+	status, err := sqlorm.Max[string](&repo, nil, "status")
+	require.Nil(t, err)
+	require.Equal(t, "void", status)
+
+	lastID, err := sqlorm.Max[int64](&repo, nil, "id")
+	require.Nil(t, err)
+	require.Greater(t, lastID, int64(0))
+
+	none, err := sqlorm.Min[time.Time](&repo, map[string]any{"status": "unknown"}, "created_at")
+	require.Nil(t, err)
+	require.True(t, none.IsZero())
+
+	// No rows
+	sum, err = sqlorm.Sum[float64](&repo, map[string]any{"status": "unknown"}, "amount")
+	require.Nil(t, err)
+	require.Equal(t, float64(0), sum)
+
+	// Aggregates cannot lock rows
+	_, err = sqlorm.Max[int64](&repo, nil, "id", sqlorm.FindOneOptions{Lock: &sqlorm.Lock{}})
+	require.ErrorIs(t, err, sqlorm.ErrLockAggregate)
+
+	// Invalid column
+	_, err = sqlorm.Sum[float64](&repo, nil, "amount); DROP TABLE invoices; --")
+	require.NotNil(t, err)
+
+	// Group by
+	type StatusTotal struct {
+		Status string
+		Total  float64
+		Count  int
+	}
+	var totals []StatusTotal
+	err = repo.GroupBy(func(qb *sqlorm.QueryBuilder) {
+		qb.NotEqual("status", "void")
+	}, sqlorm.GroupOptions{
+		Columns: []string{"status"},
+		Aggregates: []sqlorm.Aggregate{
+			{Func: sqlorm.AggSum, Column: "amount", Alias: "total"},
+			{Func: sqlorm.AggCount, Column: "*", Alias: "count"},
+		},
+		Having: []sqlorm.HavingCondition{
+			{Func: sqlorm.AggSum, Column: "amount", Operator: ">", Value: 10},
+		},
+		Order: []string{"status"},
+	}, &totals)
+	require.Nil(t, err)
+	require.Len(t, totals, 1)
+	require.Equal(t, "paid", totals[0].Status)
+	require.Equal(t, float64(40), totals[0].Total)
+	require.Equal(t, 2, totals[0].Count)
+
+	err = repo.GroupBy(nil, sqlorm.GroupOptions{
+		Columns: []string{"status"},
+		Having: []sqlorm.HavingCondition{
+			{Func: sqlorm.AggSum, Column: "amount", Operator: "> 0 OR 1 =", Value: 1},
+		},
+	}, &totals)
+	require.NotNil(t, err)
+
+	err = repo.GroupBy(nil, sqlorm.GroupOptions{
+		Aggregates: []sqlorm.Aggregate{{Func: "pg_sleep", Column: "amount"}},
+	}, &totals)
+	require.NotNil(t, err)
+
+	// Order accepts columns and aggregate aliases only
+	err = repo.GroupBy(nil, sqlorm.GroupOptions{
+		Columns:    []string{"status"},
+		Aggregates: []sqlorm.Aggregate{{Func: sqlorm.AggSum, Column: "amount", Alias: "total"}},
+		Order:      []string{"total desc"},
+	}, &totals)
+	require.Nil(t, err)
+	require.Equal(t, "void", totals[0].Status)
+
+	err = repo.GroupBy(nil, sqlorm.GroupOptions{
+		Columns: []string{"status"},
+		Order:   []string{"(SELECT pg_sleep(10))"},
+	}, &totals)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+}
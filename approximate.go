@@ -0,0 +1,111 @@
+package sqlorm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const DefaultApproximateThreshold = 100000
+
+type CountOptions struct {
+	// Approximate returns the planner estimate instead of counting rows:
+	// pg_class.reltuples for a whole table, EXPLAIN otherwise.
+	Approximate bool
+	// Threshold is the estimate under which the exact count is run anyway,
+	// DefaultApproximateThreshold when zero.
+	Threshold int64
+}
+
+type explainPlan struct {
+	Plan struct {
+		Rows float64 `json:"Plan Rows"`
+	} `json:"Plan"`
+}
+
+// approximateCount estimates the rows of the prepared count query tx and
+// falls back to an exact count for small or unknown estimates.
+func (repo *Repository[M]) approximateCount(tx *gorm.DB, opt CountOptions) (int64, error) {
+	threshold := opt.Threshold
+	if threshold <= 0 {
+		threshold = DefaultApproximateThreshold
+	}
+
+	var estimate int64
+	var err error
+	if repo.isWholeTable(tx) {
+		estimate, err = repo.tableEstimate(tx)
+	} else {
+		estimate, err = repo.explainEstimate(tx)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if estimate >= threshold {
+		return estimate, nil
+	}
+
+	var count int64
+	result := tx.Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// isWholeTable reports whether tx counts every row of the table, with no
+// condition, join, distinct or soft delete scope.
+func (repo *Repository[M]) isWholeTable(tx *gorm.DB) bool {
+	stmt := tx.Statement
+	if where, ok := stmt.Clauses["WHERE"]; ok {
+		if expr, ok := where.Expression.(clause.Where); !ok || len(expr.Exprs) > 0 {
+			return false
+		}
+	}
+	if len(stmt.Joins) > 0 || stmt.Distinct {
+		return false
+	}
+	if _, err := repo.deletedAtField(); err == nil && !stmt.Unscoped {
+		return false
+	}
+	return true
+}
+
+func (repo *Repository[M]) tableEstimate(tx *gorm.DB) (int64, error) {
+	sch, err := repo.schema()
+	if err != nil {
+		return 0, err
+	}
+	var estimate int64
+	result := tx.Session(&gorm.Session{NewDB: true}).
+		Raw("SELECT COALESCE(MAX(reltuples), -1)::bigint FROM pg_class WHERE oid = to_regclass(?)", tx.Statement.Quote(sch.Table)).
+		Scan(&estimate)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return estimate, nil
+}
+
+func (repo *Repository[M]) explainEstimate(tx *gorm.DB) (int64, error) {
+	var records []M
+	dry := tx.Session(&gorm.Session{DryRun: true}).Find(&records)
+	if dry.Error != nil {
+		return 0, dry.Error
+	}
+	stmt := dry.Statement
+
+	// The statement goes to the connection as built: passed through Raw, an
+	// @@ or @> operator would be taken for a named parameter.
+	var raw string
+	row := tx.Statement.ConnPool.QueryRowContext(tx.Statement.Context, "EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...)
+	if err := row.Scan(&raw); err != nil {
+		return 0, err
+	}
+	var plans []explainPlan
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil || len(plans) == 0 {
+		return 0, fmt.Errorf("sqlorm: unexpected explain output %q", raw)
+	}
+	return int64(plans[0].Plan.Rows), nil
+}
@@ -0,0 +1,90 @@
+package sqlorm
+
+import "github.com/tinh-tinh/tinhtinh/v2/common"
+
+type BatchOptions struct {
+	Select      []string
+	Order       []string
+	WithDeleted bool
+	Related     []string
+	Separate    bool
+	// Keyset pages through the rows by their sort keys instead of OFFSET, so
+	// rows inserted or deleted while processing are neither skipped nor
+	// repeated. Order entries must then be plain "column [asc|desc]".
+	Keyset bool
+}
+
+// FindInBatches processes the rows matched by where in chunks of size rows.
+// fc receives every batch with its 1-based number; returning an error stops
+// the iteration and the error is returned.
+func (repo *Repository[M]) FindInBatches(where Query, size int, fc func(batch []*M, n int) error, options ...BatchOptions) error {
+	if size <= 0 {
+		size = DefaultPerPage
+	}
+
+	var opt BatchOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+
+	if opt.Keyset {
+		cursor := CursorOptions{
+			Limit:       size,
+			OrderBy:     opt.Order,
+			Select:      opt.Select,
+			WithDeleted: opt.WithDeleted,
+			Related:     opt.Related,
+			Separate:    opt.Separate,
+		}
+		for n := 1; ; n++ {
+			page, err := repo.FindPage(where, cursor)
+			if err != nil {
+				return err
+			}
+			if len(page.Items) == 0 {
+				return nil
+			}
+			if err := fc(page.Items, n); err != nil {
+				return err
+			}
+			if page.NextCursor == "" {
+				return nil
+			}
+			cursor.After = page.NextCursor
+		}
+	}
+
+	if len(opt.Order) == 0 {
+		sch, err := repo.schema()
+		if err != nil {
+			return err
+		}
+		if sch.PrioritizedPrimaryField != nil {
+			opt.Order = []string{sch.PrioritizedPrimaryField.DBName}
+		}
+	}
+	find := FindOptions{
+		Select:      opt.Select,
+		Order:       opt.Order,
+		WithDeleted: opt.WithDeleted,
+		Related:     opt.Related,
+		Separate:    opt.Separate,
+		Limit:       size,
+	}
+	for n := 1; ; n++ {
+		find.Offset = (n - 1) * size
+		batch, err := repo.FindAll(where, find)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fc(batch, n); err != nil {
+			return err
+		}
+		if len(batch) < size {
+			return nil
+		}
+	}
+}
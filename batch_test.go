@@ -0,0 +1,76 @@
+package sqlorm_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+func Test_FindInBatches(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type BatchTodo struct {
+		gorm.Model
+		Name      string `gorm:"type:varchar(255);not null"`
+		Processed bool
+	}
+	err := db.AutoMigrate(&BatchTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[BatchTodo]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	var todos []*BatchTodo
+	for i := 0; i < 25; i++ {
+		todos = append(todos, &BatchTodo{Name: fmt.Sprintf("todo %02d", i)})
+	}
+	_, err = repo.BatchCreate(todos, 30)
+	require.Nil(t, err)
+
+	// Offset mode
+	var sizes []int
+	var batches []int
+	err = repo.FindInBatches(nil, 10, func(batch []*BatchTodo, n int) error {
+		sizes = append(sizes, len(batch))
+		batches = append(batches, n)
+		return nil
+	}, sqlorm.BatchOptions{Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Equal(t, []int{10, 10, 5}, sizes)
+	require.Equal(t, []int{1, 2, 3}, batches)
+
+	// Keyset mode does not skip rows that stop matching while processing
+	processed := 0
+	err = repo.FindInBatches(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("processed", false)
+	}, 10, func(batch []*BatchTodo, n int) error {
+		for _, todo := range batch {
+			_, err := repo.UpdateByID(todo.ID, map[string]any{"Processed": true})
+			if err != nil {
+				return err
+			}
+		}
+		processed += len(batch)
+		return nil
+	}, sqlorm.BatchOptions{Keyset: true, Order: []string{"name desc"}})
+	require.Nil(t, err)
+	require.Equal(t, 25, processed)
+
+	count, err := repo.Count(map[string]any{"processed": false})
+	require.Nil(t, err)
+	require.Equal(t, int64(0), count)
+
+	// Callback errors stop the iteration
+	calls := 0
+	err = repo.FindInBatches(nil, 10, func(batch []*BatchTodo, n int) error {
+		calls++
+		return errors.New("stop")
+	})
+	require.NotNil(t, err)
+	require.Equal(t, 1, calls)
+}
@@ -1,6 +1,8 @@
 package sqlorm
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
@@ -26,149 +28,475 @@ func IsQueryBuilder(q Query) bool {
 	return typeQ.Kind() == reflect.Func
 }
 
+var (
+	ErrInvalidColumn = errors.New("sqlorm: invalid column")
+	ErrInvalidJoin   = errors.New("sqlorm: invalid join")
+)
+
 type QueryBuilder struct {
-	qb *gorm.DB
+	qb     *gorm.DB
+	errs   []error
+	strict bool
+	raw    bool
 }
 
-func (q *QueryBuilder) Equal(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " = ?"
-	q.qb = q.qb.Where(query, value)
+// Strict makes the repository also reject raw SQL added with Raw, on top of
+// the invalid conditions every query is rejected for.
+func (q *QueryBuilder) Strict() *QueryBuilder {
+	q.strict = true
 	return q
 }
 
+// Err returns the errors recorded while building the query, such as
+// conditions with an invalid column. The repository returns them instead of
+// running the query.
+func (q *QueryBuilder) Err() error {
+	return errors.Join(q.errs...)
+}
+
+type conjunction int
+
+const (
+	and conjunction = iota
+	or
+	not
+)
+
+func (q *QueryBuilder) Equal(column string, value interface{}) *QueryBuilder {
+	return q.condition(and, column, " = ?", value)
+}
+
 func (q *QueryBuilder) Not(column string, args ...interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " = ?"
-	q.qb = q.qb.Not(query, args...)
-	return q
+	return q.condition(not, column, " = ?", args...)
 }
 
 func (q *QueryBuilder) Or(column string, args ...interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " = ?"
-	q.qb = q.qb.Or(query, args...)
-	return q
+	return q.condition(or, column, " = ?", args...)
 }
 
 func (q *QueryBuilder) In(column string, values ...interface{}) *QueryBuilder {
-	placeholders := make([]string, len(values))
-	for i := range values {
-		placeholders[i] = "?"
-	}
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " IN (" + strings.Join(placeholders, ", ") + ")"
-	q.qb = q.qb.Where(query, values...)
-	return q
+	return q.condition(and, column, " IN ("+placeholders(len(values))+")", values...)
 }
 
 func (q *QueryBuilder) MoreThan(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " > ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " > ?", value)
 }
 
 func (q *QueryBuilder) MoreThanOrEqual(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " >= ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " >= ?", value)
 }
 
 func (q *QueryBuilder) LessThan(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " < ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " < ?", value)
 }
 
 func (q *QueryBuilder) LessThanOrEqual(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " <= ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " <= ?", value)
 }
 
 func (q *QueryBuilder) Like(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " LIKE ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " LIKE ?", value)
 }
 
 func (q *QueryBuilder) ILike(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
-		return q
-	}
-	query := column + " ILIKE ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " ILIKE ?", value)
 }
 
 func (q *QueryBuilder) Between(column string, start interface{}, end interface{}) *QueryBuilder {
+	return q.condition(and, column, " BETWEEN ? AND ?", start, end)
+}
+
+func (q *QueryBuilder) NotEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(and, column, " <> ?", value)
+}
+
+func (q *QueryBuilder) NotIn(column string, values ...interface{}) *QueryBuilder {
+	return q.condition(and, column, " NOT IN ("+placeholders(len(values))+")", values...)
+}
+
+func (q *QueryBuilder) IsNull(column string) *QueryBuilder {
+	return q.condition(and, column, " IS NULL")
+}
+
+func (q *QueryBuilder) IsNotNull(column string) *QueryBuilder {
+	return q.condition(and, column, " IS NOT NULL")
+}
+
+func (q *QueryBuilder) NotLike(column string, value interface{}) *QueryBuilder {
+	return q.condition(and, column, " NOT LIKE ?", value)
+}
+
+func (q *QueryBuilder) NotILike(column string, value interface{}) *QueryBuilder {
+	return q.condition(and, column, " NOT ILIKE ?", value)
+}
+
+func (q *QueryBuilder) NotBetween(column string, start interface{}, end interface{}) *QueryBuilder {
+	return q.condition(and, column, " NOT BETWEEN ? AND ?", start, end)
+}
+
+// StartsWith matches values beginning with prefix. Wildcards in prefix are
+// escaped, so it is matched literally.
+func (q *QueryBuilder) StartsWith(column string, prefix string) *QueryBuilder {
+	return q.condition(and, column, likeEscaped, escapeLike(prefix)+"%")
+}
+
+// EndsWith matches values ending with suffix, matched literally.
+func (q *QueryBuilder) EndsWith(column string, suffix string) *QueryBuilder {
+	return q.condition(and, column, likeEscaped, "%"+escapeLike(suffix))
+}
+
+// Contains matches values containing substr, matched literally.
+func (q *QueryBuilder) Contains(column string, substr string) *QueryBuilder {
+	return q.condition(and, column, likeEscaped, "%"+escapeLike(substr)+"%")
+}
+
+// OR forms
+
+func (q *QueryBuilder) OrEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " = ?", value)
+}
+
+func (q *QueryBuilder) OrNotEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " <> ?", value)
+}
+
+func (q *QueryBuilder) OrIn(column string, values ...interface{}) *QueryBuilder {
+	return q.condition(or, column, " IN ("+placeholders(len(values))+")", values...)
+}
+
+func (q *QueryBuilder) OrNotIn(column string, values ...interface{}) *QueryBuilder {
+	return q.condition(or, column, " NOT IN ("+placeholders(len(values))+")", values...)
+}
+
+func (q *QueryBuilder) OrMoreThan(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " > ?", value)
+}
+
+func (q *QueryBuilder) OrMoreThanOrEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " >= ?", value)
+}
+
+func (q *QueryBuilder) OrLessThan(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " < ?", value)
+}
+
+func (q *QueryBuilder) OrLessThanOrEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " <= ?", value)
+}
+
+func (q *QueryBuilder) OrLike(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " LIKE ?", value)
+}
+
+func (q *QueryBuilder) OrNotLike(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " NOT LIKE ?", value)
+}
+
+func (q *QueryBuilder) OrILike(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " ILIKE ?", value)
+}
+
+func (q *QueryBuilder) OrNotILike(column string, value interface{}) *QueryBuilder {
+	return q.condition(or, column, " NOT ILIKE ?", value)
+}
+
+func (q *QueryBuilder) OrBetween(column string, start interface{}, end interface{}) *QueryBuilder {
+	return q.condition(or, column, " BETWEEN ? AND ?", start, end)
+}
+
+func (q *QueryBuilder) OrNotBetween(column string, start interface{}, end interface{}) *QueryBuilder {
+	return q.condition(or, column, " NOT BETWEEN ? AND ?", start, end)
+}
+
+func (q *QueryBuilder) OrIsNull(column string) *QueryBuilder {
+	return q.condition(or, column, " IS NULL")
+}
+
+func (q *QueryBuilder) OrIsNotNull(column string) *QueryBuilder {
+	return q.condition(or, column, " IS NOT NULL")
+}
+
+func (q *QueryBuilder) OrStartsWith(column string, prefix string) *QueryBuilder {
+	return q.condition(or, column, likeEscaped, escapeLike(prefix)+"%")
+}
+
+func (q *QueryBuilder) OrEndsWith(column string, suffix string) *QueryBuilder {
+	return q.condition(or, column, likeEscaped, "%"+escapeLike(suffix))
+}
+
+func (q *QueryBuilder) OrContains(column string, substr string) *QueryBuilder {
+	return q.condition(or, column, likeEscaped, "%"+escapeLike(substr)+"%")
+}
+
+// NOT forms. The negation of Equal, In, Like, ILike, Between and IsNull are
+// NotEqual, NotIn, NotLike, NotILike, NotBetween and IsNotNull.
+
+func (q *QueryBuilder) NotMoreThan(column string, value interface{}) *QueryBuilder {
+	return q.condition(not, column, " > ?", value)
+}
+
+func (q *QueryBuilder) NotMoreThanOrEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(not, column, " >= ?", value)
+}
+
+func (q *QueryBuilder) NotLessThan(column string, value interface{}) *QueryBuilder {
+	return q.condition(not, column, " < ?", value)
+}
+
+func (q *QueryBuilder) NotLessThanOrEqual(column string, value interface{}) *QueryBuilder {
+	return q.condition(not, column, " <= ?", value)
+}
+
+func (q *QueryBuilder) NotStartsWith(column string, prefix string) *QueryBuilder {
+	return q.condition(not, column, likeEscaped, escapeLike(prefix)+"%")
+}
+
+func (q *QueryBuilder) NotEndsWith(column string, suffix string) *QueryBuilder {
+	return q.condition(not, column, likeEscaped, "%"+escapeLike(suffix))
+}
+
+func (q *QueryBuilder) NotContains(column string, substr string) *QueryBuilder {
+	return q.condition(not, column, likeEscaped, "%"+escapeLike(substr)+"%")
+}
+
+func (q *QueryBuilder) condition(conj conjunction, column string, expr string, args ...interface{}) *QueryBuilder {
 	if !isValidColumn(column) {
+		q.errs = append(q.errs, fmt.Errorf("%w %q", ErrInvalidColumn, column))
 		return q
 	}
-	query := column + " BETWEEN ? AND ?"
-	q.qb = q.qb.Where(query, start, end)
+	query := column + expr
+	switch conj {
+	case or:
+		q.qb = q.qb.Or(query, args...)
+	case not:
+		q.qb = q.qb.Not(query, args...)
+	default:
+		q.qb = q.qb.Where(query, args...)
+	}
 	return q
 }
 
-func (q *QueryBuilder) NotEqual(column string, value interface{}) *QueryBuilder {
-	if !isValidColumn(column) {
+// Where adds the conditions built by fnc as one parenthesized group joined
+// with AND, e.g. qb.Where(func(g) { g.Equal("a", 1).Or("b", 2) }) produces
+// (a = 1 OR b = 2). Groups can be nested to any depth.
+func (q *QueryBuilder) Where(fnc func(g *QueryBuilder)) *QueryBuilder {
+	g := q.group()
+	fnc(g)
+	q.merge(g)
+	q.qb = q.qb.Where(g.qb)
+	return q
+}
+
+// OrWhere adds the conditions built by fnc as one parenthesized group joined
+// with OR.
+func (q *QueryBuilder) OrWhere(fnc func(g *QueryBuilder)) *QueryBuilder {
+	g := q.group()
+	fnc(g)
+	q.merge(g)
+	q.qb = q.qb.Or(g.qb)
+	return q
+}
+
+// NotWhere adds the negation of the conditions built by fnc as one group.
+func (q *QueryBuilder) NotWhere(fnc func(g *QueryBuilder)) *QueryBuilder {
+	g := q.group()
+	fnc(g)
+	q.merge(g)
+	q.qb = q.qb.Not(g.qb)
+	return q
+}
+
+func (q *QueryBuilder) group() *QueryBuilder {
+	return &QueryBuilder{qb: q.qb.Session(&gorm.Session{NewDB: true}), strict: q.strict}
+}
+
+func (q *QueryBuilder) merge(g *QueryBuilder) {
+	q.errs = append(q.errs, g.errs...)
+	q.strict = q.strict || g.strict
+	q.raw = q.raw || g.raw
+	q.qb = inheritJoins(q.qb, g.qb)
+}
+
+// inheritJoins copies the joins added on a grouped condition to tx, since
+// gorm only takes the WHERE clause from a grouped *gorm.DB.
+func inheritJoins(tx *gorm.DB, group *gorm.DB) *gorm.DB {
+	for _, join := range group.Statement.Joins {
+		tx = tx.Joins(join.Name, join.Conds...)
+	}
+	return tx
+}
+
+// EqualColumn compares two columns, e.g. to correlate a subquery with the
+// outer query: EqualColumn("orders.customer_id", "customers.id").
+func (q *QueryBuilder) EqualColumn(column string, other string) *QueryBuilder {
+	if !isValidColumn(other) {
+		q.errs = append(q.errs, fmt.Errorf("%w %q", ErrInvalidColumn, other))
 		return q
 	}
-	query := column + " <> ?"
-	q.qb = q.qb.Where(query, value)
-	return q
+	return q.condition(and, column, " = "+other)
 }
 
-func (q *QueryBuilder) NotIn(column string, values ...interface{}) *QueryBuilder {
-	placeholders := make([]string, len(values))
-	for i := range values {
-		placeholders[i] = "?"
+// Select sets the columns returned by a subquery.
+func (q *QueryBuilder) Select(columns ...string) *QueryBuilder {
+	for _, column := range columns {
+		if !isValidColumn(column) {
+			q.errs = append(q.errs, fmt.Errorf("%w %q", ErrInvalidColumn, column))
+			return q
+		}
 	}
-	if !isValidColumn(column) {
+	q.qb = q.qb.Select(columns)
+	return q
+}
+
+// InSubquery matches rows whose column is in the result of a subquery built
+// by fnc against model, which is either a model value or a repository, by
+// value or by pointer. The subquery selects the primary key of model unless
+// fnc calls Select.
+func (q *QueryBuilder) InSubquery(column string, fnc func(sub *QueryBuilder), model interface{}) *QueryBuilder {
+	return q.subquery(and, column+" IN (?)", column, fnc, model)
+}
+
+func (q *QueryBuilder) NotInSubquery(column string, fnc func(sub *QueryBuilder), model interface{}) *QueryBuilder {
+	return q.subquery(and, column+" NOT IN (?)", column, fnc, model)
+}
+
+// Exists matches rows for which the subquery built by fnc against model
+// returns at least one row.
+func (q *QueryBuilder) Exists(fnc func(sub *QueryBuilder), model interface{}) *QueryBuilder {
+	return q.subquery(and, "EXISTS (?)", "", fnc, model)
+}
+
+func (q *QueryBuilder) NotExists(fnc func(sub *QueryBuilder), model interface{}) *QueryBuilder {
+	return q.subquery(not, "EXISTS (?)", "", fnc, model)
+}
+
+type modeler interface {
+	newModel() interface{}
+}
+
+func (q *QueryBuilder) subquery(conj conjunction, query string, column string, fnc func(sub *QueryBuilder), model interface{}) *QueryBuilder {
+	if column != "" && !isValidColumn(column) {
+		q.errs = append(q.errs, fmt.Errorf("%w %q", ErrInvalidColumn, column))
 		return q
 	}
-	query := column + " NOT IN (" + strings.Join(placeholders, ", ") + ")"
-	q.qb = q.qb.Where(query, values...)
+	if m, ok := model.(modeler); ok {
+		model = m.newModel()
+	}
+
+	sub := &QueryBuilder{qb: q.qb.Session(&gorm.Session{NewDB: true}).Model(model), strict: q.strict}
+	if fnc != nil {
+		fnc(sub)
+	}
+	q.errs = append(q.errs, sub.errs...)
+	q.strict = q.strict || sub.strict
+	q.raw = q.raw || sub.raw
+
+	if len(sub.qb.Statement.Selects) == 0 {
+		if column == "" {
+			sub.qb = sub.qb.Select("1")
+		} else {
+			stmt := &gorm.Statement{DB: q.qb}
+			if err := stmt.Parse(model); err != nil {
+				q.errs = append(q.errs, err)
+				return q
+			}
+			if stmt.Schema.PrioritizedPrimaryField == nil {
+				q.errs = append(q.errs, fmt.Errorf("sqlorm: %s has no primary key to select", stmt.Schema.Table))
+				return q
+			}
+			sub.qb = sub.qb.Select(stmt.Schema.Table + "." + stmt.Schema.PrioritizedPrimaryField.DBName)
+		}
+	}
+
+	switch conj {
+	case not:
+		q.qb = q.qb.Not(query, sub.qb)
+	default:
+		q.qb = q.qb.Where(query, sub.qb)
+	}
 	return q
 }
 
-func (q *QueryBuilder) IsNull(column string) *QueryBuilder {
-	if !isValidColumn(column) {
+// InnerJoin joins table, optionally followed by an alias ("orders o" or
+// "orders AS o"), on conditions comparing columns such as
+// "o.user_id = users.id". Values can be bound with ? placeholders.
+func (q *QueryBuilder) InnerJoin(table string, on string, args ...interface{}) *QueryBuilder {
+	return q.join("INNER JOIN", table, on, args...)
+}
+
+func (q *QueryBuilder) LeftJoin(table string, on string, args ...interface{}) *QueryBuilder {
+	return q.join("LEFT JOIN", table, on, args...)
+}
+
+func (q *QueryBuilder) RightJoin(table string, on string, args ...interface{}) *QueryBuilder {
+	return q.join("RIGHT JOIN", table, on, args...)
+}
+
+func (q *QueryBuilder) join(kind string, table string, on string, args ...interface{}) *QueryBuilder {
+	if !isValidTable(table) {
+		q.errs = append(q.errs, fmt.Errorf("%w: table %q", ErrInvalidJoin, table))
 		return q
 	}
-	query := column + " IS NULL"
-	q.qb = q.qb.Where(query)
+	if !isValidJoinCondition(on) {
+		q.errs = append(q.errs, fmt.Errorf("%w: condition %q", ErrInvalidJoin, on))
+		return q
+	}
+	q.qb = q.qb.Joins(kind+" "+table+" ON "+on, args...)
 	return q
 }
 
 func (q *QueryBuilder) Raw(sql string, values ...interface{}) *QueryBuilder {
+	q.raw = true
 	q.qb = q.qb.Raw(sql, values...)
 	return q
 }
 
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// likeEscaped uses "!" as the escape character since, unlike the backslash,
+// it needs no escaping inside SQL string literals on any dialect.
+const likeEscaped = " LIKE ? ESCAPE '!'"
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
 func isValidColumn(column string) bool {
 	return validColumnRegex.MatchString(column)
 }
+
+// validJoinPredicateRegex matches a single comparison between a column and
+// another column or a placeholder, e.g. o.user_id = users.id or o.status = ?
+var validJoinPredicateRegex = regexp.MustCompile(`^\s*[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?\s*(=|<>|!=|<=|>=|<|>)\s*([a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?|\?)\s*$`)
+
+var joinAndRegex = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+func isValidTable(table string) bool {
+	parts := strings.Fields(table)
+	switch len(parts) {
+	case 1:
+		return isValidColumn(parts[0])
+	case 2:
+		return isValidColumn(parts[0]) && isValidAlias(parts[1])
+	case 3:
+		return isValidColumn(parts[0]) && strings.EqualFold(parts[1], "AS") && isValidAlias(parts[2])
+	}
+	return false
+}
+
+func isValidAlias(alias string) bool {
+	return isValidColumn(alias) && !strings.Contains(alias, ".")
+}
+
+func isValidJoinCondition(on string) bool {
+	for _, predicate := range joinAndRegex.Split(on, -1) {
+		if !validJoinPredicateRegex.MatchString(predicate) {
+			return false
+		}
+	}
+	return true
+}
@@ -163,6 +163,52 @@ func Test_QueryBuilder(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, false, exist)
 
+	// Where group
+	docs, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Where(func(g *sqlorm.QueryBuilder) {
+			g.Equal("Name", "test").Or("Priority", 3)
+		}).Equal("Status", "active")
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(docs))
+	require.Equal(t, "test", docs[0].Name)
+	require.Equal(t, "test3", docs[1].Name)
+
+	// Or where group
+	docs, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("Priority", 2).OrWhere(func(g *sqlorm.QueryBuilder) {
+			g.Equal("Name", "test").Equal("Priority", 1)
+		})
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(docs))
+	require.Equal(t, "test", docs[0].Name)
+	require.Equal(t, "test2", docs[1].Name)
+
+	// Not where group
+	docs, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.NotWhere(func(g *sqlorm.QueryBuilder) {
+			g.Equal("Name", "test").Or("Name", "test2")
+		})
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(docs))
+	require.Equal(t, "test3", docs[0].Name)
+
+	// Nested groups
+	docs, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Where(func(g *sqlorm.QueryBuilder) {
+			g.Equal("Priority", 1).OrWhere(func(h *sqlorm.QueryBuilder) {
+				h.Equal("Status", "active").Where(func(i *sqlorm.QueryBuilder) {
+					i.Equal("Name", "test3").Or("Name", "unknown")
+				})
+			})
+		}).NotEqual("Name", "test")
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(docs))
+	require.Equal(t, "test3", docs[0].Name)
+
 	// Raw
 	docs, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 		qb.Raw("SELECT * FROM documents WHERE Name = ?", "test")
@@ -172,6 +218,155 @@ func Test_QueryBuilder(t *testing.T) {
 	require.Equal(t, "test", docs[0].Name)
 }
 
+func Test_QueryBuilderOperators(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type Product struct {
+		gorm.Model
+		Name     string  `gorm:"type:varchar(255);not null"`
+		Priority int     `gorm:"type:int"`
+		Note     *string `gorm:"type:varchar(255)"`
+	}
+	err := db.AutoMigrate(&Product{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[Product]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	note := "note"
+	_, err = repo.BatchCreate([]*Product{
+		{Name: "apple", Priority: 1, Note: &note},
+		{Name: "50% off", Priority: 2},
+		{Name: "500 off", Priority: 3, Note: &note},
+		{Name: "banana_split", Priority: 4},
+		{Name: "bananaXsplit", Priority: 5},
+	}, 10)
+	require.Nil(t, err)
+
+	names := func(where func(qb *sqlorm.QueryBuilder)) []string {
+		products, err := repo.FindAll(where, sqlorm.FindOptions{Order: []string{"priority"}})
+		require.Nil(t, err)
+		var result []string
+		for _, product := range products {
+			result = append(result, product.Name)
+		}
+		return result
+	}
+
+	// AND forms
+	require.Equal(t, []string{"apple", "500 off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.IsNotNull("note")
+	}))
+	require.Equal(t, []string{"apple", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotLike("name", "%off")
+	}))
+	require.Equal(t, []string{"apple", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotILike("name", "%OFF")
+	}))
+	require.Equal(t, []string{"apple", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotBetween("priority", 2, 4)
+	}))
+	require.Equal(t, []string{"50% off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.StartsWith("name", "50%")
+	}))
+	require.Equal(t, []string{"banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.EndsWith("name", "_split")
+	}))
+	require.Equal(t, []string{"banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Contains("name", "a_s")
+	}))
+
+	// OR forms
+	require.Equal(t, []string{"apple", "500 off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrEqual("priority", 3)
+	}))
+	require.Equal(t, []string{"apple", "50% off", "500 off", "banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrNotEqual("priority", 5)
+	}))
+	require.Equal(t, []string{"apple", "500 off", "banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrIn("priority", 3, 4)
+	}))
+	require.Equal(t, []string{"apple", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrNotIn("priority", 1, 2, 3)
+	}))
+	require.Equal(t, []string{"apple", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrMoreThan("priority", 4)
+	}))
+	require.Equal(t, []string{"apple", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrMoreThanOrEqual("priority", 4)
+	}))
+	require.Equal(t, []string{"apple", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 5).OrLessThan("priority", 2)
+	}))
+	require.Equal(t, []string{"apple", "50% off", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 5).OrLessThanOrEqual("priority", 2)
+	}))
+	require.Equal(t, []string{"apple", "50% off", "500 off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrLike("name", "%off")
+	}))
+	require.Equal(t, []string{"apple", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrNotLike("name", "%off")
+	}))
+	require.Equal(t, []string{"apple", "50% off", "500 off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrILike("name", "%OFF")
+	}))
+	require.Equal(t, []string{"apple", "50% off", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 2).OrNotILike("name", "%OFF")
+	}))
+	require.Equal(t, []string{"apple", "500 off", "banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrBetween("priority", 3, 4)
+	}))
+	require.Equal(t, []string{"apple", "50% off", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 2).OrNotBetween("priority", 2, 4)
+	}))
+	require.Equal(t, []string{"50% off", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 5).OrIsNull("note")
+	}))
+	require.Equal(t, []string{"apple", "500 off", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 5).OrIsNotNull("note")
+	}))
+	require.Equal(t, []string{"apple", "50% off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrStartsWith("name", "50%")
+	}))
+	require.Equal(t, []string{"apple", "banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrEndsWith("name", "_split")
+	}))
+	require.Equal(t, []string{"apple", "banana_split"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1).OrContains("name", "a_s")
+	}))
+
+	// NOT forms
+	require.Equal(t, []string{"apple", "50% off", "500 off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotMoreThan("priority", 3)
+	}))
+	require.Equal(t, []string{"apple", "50% off"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotMoreThanOrEqual("priority", 3)
+	}))
+	require.Equal(t, []string{"500 off", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotLessThan("priority", 3)
+	}))
+	require.Equal(t, []string{"banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotLessThanOrEqual("priority", 3)
+	}))
+	require.Equal(t, []string{"apple", "500 off", "banana_split", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotStartsWith("name", "50%")
+	}))
+	require.Equal(t, []string{"apple", "50% off", "500 off", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotEndsWith("name", "_split")
+	}))
+	require.Equal(t, []string{"apple", "50% off", "500 off", "bananaXsplit"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.NotContains("name", "a_s")
+	}))
+
+	// Escape character is matched literally
+	_, err = repo.Create(&Product{Name: "wow!", Priority: 6})
+	require.Nil(t, err)
+	require.Equal(t, []string{"wow!"}, names(func(qb *sqlorm.QueryBuilder) {
+		qb.EndsWith("name", "!")
+	}))
+}
+
 func Test_IsValidColumn(t *testing.T) {
 	require.NotPanics(t, func() {
 		createDatabaseForTest("test_valid_column")
@@ -245,8 +440,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.Equal(invalidCol, "test")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -256,8 +451,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.Not(invalidCol, "test")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -267,8 +462,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.Or(invalidCol, "test")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -278,8 +473,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.In(invalidCol, "test", "test2")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -289,8 +484,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.MoreThan(invalidCol, 0)
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -300,8 +495,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.MoreThanOrEqual(invalidCol, 0)
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -311,8 +506,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.LessThan(invalidCol, 100)
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -322,8 +517,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.LessThanOrEqual(invalidCol, 100)
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -333,8 +528,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.Like(invalidCol, "%test%")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -344,8 +539,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.ILike(invalidCol, "%TEST%")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -355,8 +550,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.Between(invalidCol, 0, 100)
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -366,8 +561,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.NotEqual(invalidCol, "nonexistent")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -377,8 +572,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.NotIn(invalidCol, "nonexistent1", "nonexistent2")
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -388,8 +583,8 @@ func Test_IsValidColumn(t *testing.T) {
 			docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
 				qb.IsNull(invalidCol)
 			})
-			require.Nil(t, err)
-			require.GreaterOrEqual(t, len(docs), 1, "Invalid column %q should not filter results", invalidCol)
+			require.ErrorIs(t, err, sqlorm.ErrInvalidColumn, "Invalid column %q should be rejected", invalidCol)
+			require.Nil(t, docs)
 		}
 	})
 
@@ -453,3 +648,285 @@ func Test_IsValidColumn(t *testing.T) {
 		}
 	})
 }
+
+func Test_StrictQueryBuilder(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type StrictEntity struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255)"`
+	}
+	err := db.AutoMigrate(&StrictEntity{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[StrictEntity]{DB: db}
+	count, err := repo.Count(nil)
+	require.Nil(t, err)
+	if count == 0 {
+		_, err = repo.BatchCreate([]*StrictEntity{{Name: "a"}, {Name: "b"}}, 5)
+		require.Nil(t, err)
+	}
+
+	// Errors are recorded on the builder and returned
+	var builder *sqlorm.QueryBuilder
+	docs, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("name", "a").Equal("name; DROP TABLE users", "a")
+		builder = qb
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+	require.Nil(t, docs)
+	require.ErrorIs(t, builder.Err(), sqlorm.ErrInvalidColumn)
+
+	invalid := func(qb *sqlorm.QueryBuilder) {
+		qb.IsNull("name)--")
+	}
+
+	_, err = repo.FindOne(invalid)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	_, err = repo.Count(invalid)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	_, err = repo.Exist(invalid)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	_, _, err = repo.FindAllAndCount(invalid)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	// Errors from nested groups are propagated
+	_, err = repo.FindOne(func(qb *sqlorm.QueryBuilder) {
+		qb.Where(func(g *sqlorm.QueryBuilder) {
+			g.Equal("name", "a").OrIn("bad column", 1)
+		})
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	// Strict builder also rejects raw SQL
+	docs, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Raw("SELECT * FROM strict_entities WHERE name = ?", "a")
+	})
+	require.Nil(t, err)
+	require.Len(t, docs, 1)
+
+	_, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Strict().Raw("SELECT * FROM strict_entities WHERE name = ?", "a")
+	})
+	require.ErrorIs(t, err, sqlorm.ErrUnsafeSQL)
+
+	// Strict repository
+	strictRepo := sqlorm.Repository[StrictEntity]{DB: db, Strict: true}
+	_, err = strictRepo.FindAll(invalid)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	_, err = strictRepo.Count(func(qb *sqlorm.QueryBuilder) {
+		qb.Raw("SELECT COUNT(*) FROM strict_entities")
+	})
+	require.ErrorIs(t, err, sqlorm.ErrUnsafeSQL)
+
+	// Valid queries are unaffected
+	docs, err = strictRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("name", "a")
+	})
+	require.Nil(t, err)
+	require.GreaterOrEqual(t, len(docs), 1)
+}
+
+func Test_QueryBuilderJoin(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type JoinCustomer struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255)"`
+	}
+	type JoinOrder struct {
+		gorm.Model
+		CustomerID uint
+		Status     string `gorm:"type:varchar(50)"`
+	}
+	err := db.AutoMigrate(&JoinCustomer{}, &JoinOrder{})
+	require.Nil(t, err)
+
+	customerRepo := sqlorm.Repository[JoinCustomer]{DB: db, Strict: true}
+	orderRepo := sqlorm.Repository[JoinOrder]{DB: db}
+	err = orderRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+	err = customerRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	alice, err := customerRepo.Create(&JoinCustomer{Name: "alice"})
+	require.Nil(t, err)
+	bob, err := customerRepo.Create(&JoinCustomer{Name: "bob"})
+	require.Nil(t, err)
+	_, err = customerRepo.Create(&JoinCustomer{Name: "carol"})
+	require.Nil(t, err)
+
+	_, err = orderRepo.BatchCreate([]*JoinOrder{
+		{CustomerID: alice.ID, Status: "paid"},
+		{CustomerID: bob.ID, Status: "pending"},
+	}, 5)
+	require.Nil(t, err)
+
+	// Inner join with a condition on a joined column
+	customers, err := customerRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.InnerJoin("join_orders o", "o.customer_id = join_customers.id").Equal("o.status", "paid")
+	})
+	require.Nil(t, err)
+	require.Len(t, customers, 1)
+	require.Equal(t, "alice", customers[0].Name)
+	require.Equal(t, alice.ID, customers[0].ID)
+
+	// Bound values in the join condition
+	count, err := customerRepo.Count(func(qb *sqlorm.QueryBuilder) {
+		qb.InnerJoin("join_orders AS o", "o.customer_id = join_customers.id AND o.status = ?", "pending")
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Left join to find customers without orders
+	customers, err = customerRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.LeftJoin("join_orders o", "o.customer_id = join_customers.id").IsNull("o.id")
+	})
+	require.Nil(t, err)
+	require.Len(t, customers, 1)
+	require.Equal(t, "carol", customers[0].Name)
+
+	// Right join
+	orders, err := orderRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.RightJoin("join_customers c", "c.id = join_orders.customer_id").Equal("c.name", "bob")
+	})
+	require.Nil(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, "pending", orders[0].Status)
+
+	// Invalid tables and conditions are rejected
+	_, err = customerRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.InnerJoin("join_orders; DROP TABLE join_orders", "o.customer_id = join_customers.id")
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidJoin)
+
+	_, err = customerRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.InnerJoin("join_orders o", "o.customer_id = join_customers.id OR 1 = 1")
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidJoin)
+}
+
+func Test_QueryBuilderSubquery(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type SubAuthor struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255)"`
+	}
+	type SubPost struct {
+		gorm.Model
+		SubAuthorID uint
+		Published   bool
+	}
+	err := db.AutoMigrate(&SubAuthor{}, &SubPost{})
+	require.Nil(t, err)
+
+	authorRepo := sqlorm.Repository[SubAuthor]{DB: db, Strict: true}
+	postRepo := sqlorm.Repository[SubPost]{DB: db}
+	err = postRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+	err = authorRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	alice, err := authorRepo.Create(&SubAuthor{Name: "alice"})
+	require.Nil(t, err)
+	bob, err := authorRepo.Create(&SubAuthor{Name: "bob"})
+	require.Nil(t, err)
+	_, err = authorRepo.Create(&SubAuthor{Name: "carol"})
+	require.Nil(t, err)
+
+	_, err = postRepo.BatchCreate([]*SubPost{
+		{SubAuthorID: alice.ID, Published: true},
+		{SubAuthorID: bob.ID, Published: false},
+	}, 5)
+	require.Nil(t, err)
+
+	names := func(authors []*SubAuthor) []string {
+		var result []string
+		for _, author := range authors {
+			result = append(result, author.Name)
+		}
+		return result
+	}
+
+	// IN subquery against a model
+	authors, err := authorRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.InSubquery("id", func(sub *sqlorm.QueryBuilder) {
+			sub.Select("sub_author_id").Equal("published", true)
+		}, &SubPost{})
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"alice"}, names(authors))
+
+	// NOT IN subquery against a repository
+	authors, err = authorRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.NotInSubquery("id", func(sub *sqlorm.QueryBuilder) {
+			sub.Select("sub_author_id")
+		}, &postRepo)
+	}, sqlorm.FindOptions{Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"carol"}, names(authors))
+
+	// Correlated EXISTS
+	authors, err = authorRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Exists(func(sub *sqlorm.QueryBuilder) {
+			sub.EqualColumn("sub_posts.sub_author_id", "sub_authors.id")
+		}, &postRepo)
+	}, sqlorm.FindOptions{Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"alice", "bob"}, names(authors))
+
+	// NOT EXISTS
+	authors, err = authorRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.NotExists(func(sub *sqlorm.QueryBuilder) {
+			sub.EqualColumn("sub_posts.sub_author_id", "sub_authors.id").Equal("published", true)
+		}, &SubPost{})
+	}, sqlorm.FindOptions{Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"bob", "carol"}, names(authors))
+
+	// Default selection is the primary key
+	count, err := postRepo.Count(func(qb *sqlorm.QueryBuilder) {
+		qb.InSubquery("sub_author_id", func(sub *sqlorm.QueryBuilder) {
+			sub.Equal("name", "bob")
+		}, &authorRepo)
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// A repository can be passed by value too
+	count, err = postRepo.Count(func(qb *sqlorm.QueryBuilder) {
+		qb.InSubquery("sub_author_id", func(sub *sqlorm.QueryBuilder) {
+			sub.Equal("name", "bob")
+		}, authorRepo)
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Errors inside the subquery are reported
+	_, err = authorRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Exists(func(sub *sqlorm.QueryBuilder) {
+			sub.EqualColumn("sub_posts.sub_author_id", "sub_authors.id; DROP TABLE sub_posts")
+		}, &SubPost{})
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	// Strict mode rejects raw SQL inside a subquery
+	_, err = authorRepo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Exists(func(sub *sqlorm.QueryBuilder) {
+			sub.Raw("SELECT 1 FROM sub_posts")
+		}, &SubPost{})
+	})
+	require.ErrorIs(t, err, sqlorm.ErrUnsafeSQL)
+
+	_, err = postRepo.Count(func(qb *sqlorm.QueryBuilder) {
+		qb.Strict().InSubquery("sub_author_id", func(sub *sqlorm.QueryBuilder) {
+			sub.Raw("SELECT id FROM sub_authors")
+		}, &authorRepo)
+	})
+	require.ErrorIs(t, err, sqlorm.ErrUnsafeSQL)
+}
@@ -0,0 +1,277 @@
+package sqlorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// QueryChain collects conditions and find options fluently and runs them with
+// one of its terminals, e.g.
+//
+//	repo.Query().Where(func(qb *QueryBuilder) { qb.Equal("active", true) }).
+//		OrderBy("created_at desc").Limit(10).With("Posts").Find()
+type QueryChain[M any] struct {
+	repo   *Repository[M]
+	wheres []Query
+	opt    FindOptions
+	errs   []error
+}
+
+func (repo *Repository[M]) Query() *QueryChain[M] {
+	return &QueryChain[M]{repo: repo}
+}
+
+// Where adds a condition, either a QueryBuilder function or a value accepted
+// by gorm such as a map. Conditions of several calls are joined with AND.
+func (c *QueryChain[M]) Where(where Query) *QueryChain[M] {
+	if where != nil {
+		c.wheres = append(c.wheres, where)
+	}
+	return c
+}
+
+// OrderBy adds entries like "name" or "created_at desc".
+func (c *QueryChain[M]) OrderBy(orders ...string) *QueryChain[M] {
+	for _, order := range orders {
+		if err := validateOrder(order); err != nil {
+			c.errs = append(c.errs, err)
+			continue
+		}
+		c.opt.Order = append(c.opt.Order, order)
+	}
+	return c
+}
+
+func (c *QueryChain[M]) Select(columns ...string) *QueryChain[M] {
+	for _, column := range columns {
+		if !isValidColumn(column) {
+			c.errs = append(c.errs, fmt.Errorf("%w %q in select", ErrInvalidColumn, column))
+			continue
+		}
+		c.opt.Select = append(c.opt.Select, column)
+	}
+	return c
+}
+
+func (c *QueryChain[M]) Distinct(columns ...interface{}) *QueryChain[M] {
+	c.opt.Distinct = append(c.opt.Distinct, columns...)
+	return c
+}
+
+func (c *QueryChain[M]) Limit(limit int) *QueryChain[M] {
+	c.opt.Limit = limit
+	return c
+}
+
+func (c *QueryChain[M]) Offset(offset int) *QueryChain[M] {
+	c.opt.Offset = offset
+	return c
+}
+
+// With loads the given associations, joined in the same query unless
+// Separate is called.
+func (c *QueryChain[M]) With(related ...string) *QueryChain[M] {
+	c.opt.Related = append(c.opt.Related, related...)
+	return c
+}
+
+func (c *QueryChain[M]) Separate() *QueryChain[M] {
+	c.opt.Separate = true
+	return c
+}
+
+func (c *QueryChain[M]) WithDeleted() *QueryChain[M] {
+	c.opt.WithDeleted = true
+	return c
+}
+
+func (c *QueryChain[M]) OnlyDeleted() *QueryChain[M] {
+	c.opt.OnlyDeleted = true
+	return c
+}
+
+// Strict rejects raw SQL in the Where conditions, as Repository.Strict does.
+func (c *QueryChain[M]) Strict() *QueryChain[M] {
+	c.repo = c.repo.clone(c.repo.DB)
+	c.repo.Strict = true
+	return c
+}
+
+func (c *QueryChain[M]) Lock(lock *Lock) *QueryChain[M] {
+	c.opt.Lock = lock
+	return c
+}
+
+func (c *QueryChain[M]) Find() ([]*M, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return c.repo.FindAll(c.where(), c.opt)
+}
+
+// First returns the first matching row, or nil when there is none.
+func (c *QueryChain[M]) First() (*M, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	var model M
+	result := c.repo.findQuery(c.where(), c.opt).First(&model)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &model, nil
+}
+
+// Count counts the matching rows, ignoring Limit, Offset and OrderBy.
+func (c *QueryChain[M]) Count() (int64, error) {
+	if err := c.Err(); err != nil {
+		return 0, err
+	}
+	return c.repo.countAll(c.where(), c.opt)
+}
+
+func (c *QueryChain[M]) Exists() (bool, error) {
+	model, err := c.First()
+	if err != nil {
+		return false, err
+	}
+	return model != nil, nil
+}
+
+// Pluck scans a single column of the matching rows into dest, which must be
+// a pointer to a slice.
+func (c *QueryChain[M]) Pluck(column string, dest interface{}) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	if !isValidColumn(column) {
+		return fmt.Errorf("%w %q in pluck", ErrInvalidColumn, column)
+	}
+	var model M
+	opt := c.opt
+	opt.Select = nil
+
+	result := c.repo.findQuery(c.where(), opt).Model(&model).Pluck(column, dest)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Update applies val to every matching row. Limit, Offset and OrderBy are
+// ignored.
+func (c *QueryChain[M]) Update(val interface{}) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	tx, err := c.mutation()
+	if err != nil {
+		return err
+	}
+	var model M
+	input := MapOne[M](val)
+	var values interface{} = input
+	if field := c.repo.versionField(); field != nil {
+		values = c.repo.bumpVersion(field, input)
+	}
+
+	result := tx.Model(&model).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Delete deletes every matching row, permanently when isForceDelete is true.
+// Limit, Offset and OrderBy are ignored.
+func (c *QueryChain[M]) Delete(isForceDelete ...bool) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	tx, err := c.mutation()
+	if err != nil {
+		return err
+	}
+	var model M
+	if len(isForceDelete) > 0 && isForceDelete[0] {
+		tx = tx.Unscoped()
+	}
+
+	result := tx.Delete(&model)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Err returns the errors recorded while building the chain, such as an
+// invalid order or select column.
+func (c *QueryChain[M]) Err() error {
+	return errors.Join(c.errs...)
+}
+
+// mutation scopes an update or delete to the conditions. It fails when a
+// condition recorded an error, since dropping it would widen the statement.
+func (c *QueryChain[M]) mutation() (*gorm.DB, error) {
+	tx := c.repo.DB
+	if c.opt.WithDeleted {
+		tx = tx.Unscoped()
+	}
+	if c.opt.OnlyDeleted {
+		tx = c.repo.applyOnlyDeleted(tx)
+	}
+	if len(c.wheres) == 0 {
+		return tx.Where("1 = 1"), nil
+	}
+	tx = c.repo.applyQuery(tx, c.where())
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return tx, nil
+}
+
+// where combines the conditions into one QueryBuilder function, grouping
+// each of them so an OR inside one call does not leak into the others.
+func (c *QueryChain[M]) where() Query {
+	if len(c.wheres) == 0 {
+		return nil
+	}
+	return func(qb *QueryBuilder) {
+		for _, where := range c.wheres {
+			fnc, ok := where.(func(qb *QueryBuilder))
+			if !ok {
+				qb.qb = qb.qb.Where(where)
+				continue
+			}
+			if len(c.wheres) == 1 {
+				fnc(qb)
+				continue
+			}
+			qb.Where(fnc)
+		}
+	}
+}
+
+func validateOrder(order string) error {
+	parts := strings.Fields(order)
+	if len(parts) == 0 || len(parts) > 2 {
+		return fmt.Errorf("sqlorm: invalid order %q", order)
+	}
+	if !isValidColumn(parts[0]) {
+		return fmt.Errorf("%w %q in order", ErrInvalidColumn, parts[0])
+	}
+	if len(parts) == 2 {
+		switch strings.ToLower(parts[1]) {
+		case "asc", "desc":
+		default:
+			return fmt.Errorf("sqlorm: invalid order direction %q", parts[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package sqlorm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+type ChainAuthor struct {
+	gorm.Model
+	Name   string `gorm:"type:varchar(255);not null"`
+	Active bool
+	Posts  []ChainPost
+}
+
+type ChainPost struct {
+	gorm.Model
+	ChainAuthorID uint
+	Title         string `gorm:"type:varchar(255)"`
+}
+
+func Test_QueryChain(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&ChainAuthor{}, &ChainPost{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[ChainAuthor]{DB: db}
+	postRepo := sqlorm.Repository[ChainPost]{DB: db}
+	err = postRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	authors, err := repo.BatchCreate([]*ChainAuthor{
+		{Name: "alice", Active: true},
+		{Name: "bob", Active: true},
+		{Name: "carol", Active: false},
+		{Name: "dave", Active: true},
+	}, 4)
+	require.Nil(t, err)
+	_, err = postRepo.BatchCreate([]*ChainPost{
+		{ChainAuthorID: authors[0].ID, Title: "first"},
+		{ChainAuthorID: authors[0].ID, Title: "second"},
+	}, 2)
+	require.Nil(t, err)
+
+	active := func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("active", true)
+	}
+
+	// Find
+	result, err := repo.Query().
+		Where(active).
+		OrderBy("name desc").
+		Limit(2).
+		Offset(1).
+		Find()
+	require.Nil(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "bob", result[0].Name)
+	require.Equal(t, "alice", result[1].Name)
+
+	// Conditions of several Where calls are grouped
+	result, err = repo.Query().
+		Where(func(qb *sqlorm.QueryBuilder) {
+			qb.Equal("name", "carol").OrEqual("name", "dave")
+		}).
+		Where(active).
+		Find()
+	require.Nil(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, "dave", result[0].Name)
+
+	// First with associations
+	author, err := repo.Query().
+		Where(map[string]interface{}{"name": "alice"}).
+		With("Posts").
+		Separate().
+		First()
+	require.Nil(t, err)
+	require.NotNil(t, author)
+	require.Len(t, author.Posts, 2)
+
+	author, err = repo.Query().Where(map[string]interface{}{"name": "nobody"}).First()
+	require.Nil(t, err)
+	require.Nil(t, author)
+
+	// Count and Exists
+	count, err := repo.Query().Where(active).Limit(1).Count()
+	require.Nil(t, err)
+	require.Equal(t, int64(3), count)
+
+	exist, err := repo.Query().Where(func(qb *sqlorm.QueryBuilder) {
+		qb.StartsWith("name", "car")
+	}).Exists()
+	require.Nil(t, err)
+	require.True(t, exist)
+
+	// Pluck
+	var names []string
+	err = repo.Query().Where(active).OrderBy("name").Pluck("name", &names)
+	require.Nil(t, err)
+	require.Equal(t, []string{"alice", "bob", "dave"}, names)
+
+	// Update
+	err = repo.Query().Where(map[string]interface{}{"name": "carol"}).Update(map[string]interface{}{"active": true})
+	require.Nil(t, err)
+	count, err = repo.Query().Where(active).Count()
+	require.Nil(t, err)
+	require.Equal(t, int64(4), count)
+
+	// Delete
+	err = repo.Query().Where(func(qb *sqlorm.QueryBuilder) {
+		qb.In("name", "bob", "dave")
+	}).Delete()
+	require.Nil(t, err)
+	count, err = repo.Query().Count()
+	require.Nil(t, err)
+	require.Equal(t, int64(2), count)
+	count, err = repo.Query().OnlyDeleted().Count()
+	require.Nil(t, err)
+	require.Equal(t, int64(2), count)
+
+	// Invalid input
+	_, err = repo.Query().OrderBy("name;DROP desc").Find()
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	err = repo.Query().Pluck("name, id", &names)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	_, err = repo.Query().Strict().Where(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("name = 'x' OR 1", 1)
+	}).Find()
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	// Mutations never run without a dropped condition
+	before, err := repo.Query().Count()
+	require.Nil(t, err)
+	err = repo.Query().Where(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("stauts x", "open")
+	}).Where(map[string]interface{}{"name": "carol"}).Delete()
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	err = repo.Query().Where(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("stauts x", "open")
+	}).Update(map[string]interface{}{"name": "mallory"})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	after, err := repo.Query().Count()
+	require.Nil(t, err)
+	require.Equal(t, before, after)
+	exists, err := repo.Query().Where(map[string]interface{}{"name": "mallory"}).Exists()
+	require.Nil(t, err)
+	require.False(t, exists)
+}
@@ -0,0 +1,492 @@
+// Command sqlormgen generates typed column references for gorm models.
+//
+// For every model of the package in -dir it declares a <Model>Fields variable
+// holding one sqlorm.Field per column, so that queries can be written as
+//
+//	repo.FindAll(UserFields.Email.ILike("%@example.com"))
+//
+// and renaming a struct field breaks the build instead of the query. Add
+//
+//	//go:generate go run github.com/tinh-tinh/sqlorm/v2/cmd/sqlormgen
+//
+// next to the models and run go generate.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+const (
+	gormPath   = "gorm.io/gorm"
+	sqlormPath = "github.com/tinh-tinh/sqlorm/v2"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sqlormgen: ")
+
+	dir := flag.String("dir", ".", "directory of the models package")
+	typeNames := flag.String("type", "", "comma separated model names; defaults to every struct embedding gorm.Model or using gorm tags")
+	output := flag.String("output", "sqlorm_fields.go", "output file name, relative to -dir")
+	qualify := flag.Bool("qualify", false, "prefix columns with the table name")
+	flag.Parse()
+
+	cfg := config{Dir: *dir, Output: *output, Qualify: *qualify}
+	if *typeNames != "" {
+		cfg.Types = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type config struct {
+	Dir     string
+	Types   []string
+	Output  string
+	Qualify bool
+}
+
+type column struct {
+	Field string
+	Type  string
+	Name  string
+}
+
+type model struct {
+	Name    string
+	Columns []column
+}
+
+type generator struct {
+	pkg     string
+	structs map[string]*ast.StructType
+	files   map[string]*ast.File
+	tables  map[string]string
+	imports map[string]string
+	naming  schema.NamingStrategy
+}
+
+func generate(cfg config) ([]byte, error) {
+	g := &generator{
+		structs: make(map[string]*ast.StructType),
+		files:   make(map[string]*ast.File),
+		tables:  make(map[string]string),
+		imports: map[string]string{"sqlorm": sqlormPath},
+	}
+	if err := g.parse(cfg.Dir, cfg.Output); err != nil {
+		return nil, err
+	}
+
+	names := cfg.Types
+	if len(names) == 0 {
+		for name, st := range g.structs {
+			if g.isModel(name, st) {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no models found")
+	}
+
+	var models []model
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		st, ok := g.structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct %s not found in %s", name, cfg.Dir)
+		}
+		m := model{Name: name}
+		seen := make(map[string]bool)
+		if err := g.columns(g.files[name], st, "", "", seen, &m.Columns); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if cfg.Qualify {
+			table := g.tables[name]
+			if table == "" {
+				table = g.naming.TableName(name)
+			}
+			for i := range m.Columns {
+				m.Columns[i].Name = table + "." + m.Columns[i].Name
+			}
+		}
+		models = append(models, m)
+	}
+	return g.render(models)
+}
+
+func (g *generator) parse(dir string, output string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		if g.pkg == "" {
+			g.pkg = file.Name.Name
+		} else if g.pkg != file.Name.Name {
+			return fmt.Errorf("found packages %s and %s in %s", g.pkg, file.Name.Name, dir)
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || ts.TypeParams != nil {
+						continue
+					}
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						g.structs[ts.Name.Name] = st
+						g.files[ts.Name.Name] = file
+					}
+				}
+			case *ast.FuncDecl:
+				if name, table, ok := tableNameMethod(decl); ok {
+					g.tables[name] = table
+				}
+			}
+		}
+	}
+	if g.pkg == "" {
+		return fmt.Errorf("no Go files in %s", dir)
+	}
+	return nil
+}
+
+// tableNameMethod recognizes func (M) TableName() string { return "table" }.
+func tableNameMethod(fn *ast.FuncDecl) (string, string, bool) {
+	if fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", "", false
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", false
+	}
+	table, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", false
+	}
+	return ident.Name, table, true
+}
+
+func (g *generator) isModel(name string, st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 && g.isSelector(g.files[name], field.Type, gormPath, "Model") {
+			return true
+		}
+		if field.Tag != nil && strings.Contains(field.Tag.Value, `gorm:"`) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) columns(file *ast.File, st *ast.StructType, prefix string, fieldPrefix string, seen map[string]bool, result *[]column) error {
+	for _, field := range st.Fields.List {
+		settings := tagSettings(field)
+		if value, ok := settings["-"]; ok && value != "migration" {
+			continue
+		}
+		if isRelationTag(settings) {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			if err := g.embedded(file, field.Type, prefix, fieldPrefix, seen, result); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := settings["EMBEDDED"]; ok {
+			for _, ident := range field.Names {
+				if err := g.embedded(file, field.Type, prefix+settings["EMBEDDEDPREFIX"], fieldPrefix+ident.Name, seen, result); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		_, serialized := settings["SERIALIZER"]
+		if !serialized && g.isRelation(field.Type) {
+			continue
+		}
+
+		typ, err := g.typeString(file, field.Type)
+		if err != nil {
+			return err
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() || seen[fieldPrefix+ident.Name] {
+				continue
+			}
+			seen[fieldPrefix+ident.Name] = true
+			name := settings["COLUMN"]
+			if name == "" {
+				name = g.naming.ColumnName("", ident.Name)
+			}
+			*result = append(*result, column{Field: fieldPrefix + ident.Name, Type: typ, Name: prefix + name})
+		}
+	}
+	return nil
+}
+
+func (g *generator) embedded(file *ast.File, expr ast.Expr, prefix string, fieldPrefix string, seen map[string]bool, result *[]column) error {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch {
+	case g.isSelector(file, expr, gormPath, "Model"):
+		g.imports["time"] = "time"
+		g.imports["gorm"] = gormPath
+		for _, c := range []column{
+			{Field: "ID", Type: "uint", Name: "id"},
+			{Field: "CreatedAt", Type: "time.Time", Name: "created_at"},
+			{Field: "UpdatedAt", Type: "time.Time", Name: "updated_at"},
+			{Field: "DeletedAt", Type: "gorm.DeletedAt", Name: "deleted_at"},
+		} {
+			c.Field = fieldPrefix + c.Field
+			if !seen[c.Field] {
+				seen[c.Field] = true
+				c.Name = prefix + c.Name
+				*result = append(*result, c)
+			}
+		}
+		return nil
+	case g.isSelector(file, expr, sqlormPath, "Versioned"):
+		if !seen[fieldPrefix+"Version"] {
+			seen[fieldPrefix+"Version"] = true
+			*result = append(*result, column{Field: fieldPrefix + "Version", Type: "int64", Name: prefix + "version"})
+		}
+		return nil
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return fmt.Errorf("unsupported embedded field %s", types.ExprString(expr))
+	}
+	st, ok := g.structs[ident.Name]
+	if !ok {
+		return fmt.Errorf("embedded struct %s not found", ident.Name)
+	}
+	return g.columns(g.files[ident.Name], st, prefix, fieldPrefix, seen, result)
+}
+
+// isRelation reports whether a field refers to other models, which gorm maps
+// to associations instead of columns.
+func (g *generator) isRelation(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		_, ok := g.structs[expr.Name]
+		return ok
+	case *ast.StarExpr:
+		return g.isRelation(expr.X)
+	case *ast.ArrayType:
+		if ident, ok := expr.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return false
+		}
+		return true
+	case *ast.MapType, *ast.StructType, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return true
+	}
+	return false
+}
+
+func (g *generator) isSelector(file *ast.File, expr ast.Expr, path string, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	return importPath(file, pkg.Name) == path
+}
+
+// typeString renders the field type and records the imports it needs.
+func (g *generator) typeString(file *ast.File, expr ast.Expr) (string, error) {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		path := importPath(file, pkg.Name)
+		if path == "" {
+			err = fmt.Errorf("unknown package %s", pkg.Name)
+			return false
+		}
+		if existing, ok := g.imports[pkg.Name]; ok && existing != path {
+			err = fmt.Errorf("package name %s is used for %s and %s", pkg.Name, existing, path)
+			return false
+		}
+		g.imports[pkg.Name] = path
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	return types.ExprString(expr), nil
+}
+
+func importPath(file *ast.File, name string) string {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		alias := packageName(path)
+		if spec.Name != nil {
+			alias = spec.Name.Name
+		}
+		if alias == name {
+			return path
+		}
+	}
+	return ""
+}
+
+// packageName guesses the name of a package from its import path, skipping
+// a major version suffix such as /v2.
+func packageName(path string) string {
+	name := path[strings.LastIndex(path, "/")+1:]
+	if len(name) > 1 && name[0] == 'v' {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			trimmed := strings.TrimSuffix(path, "/"+name)
+			name = trimmed[strings.LastIndex(trimmed, "/")+1:]
+		}
+	}
+	return name
+}
+
+// tagSettings parses the gorm tag the way gorm does, with upper cased keys.
+func tagSettings(field *ast.Field) map[string]string {
+	settings := make(map[string]string)
+	if field.Tag == nil {
+		return settings
+	}
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return settings
+	}
+	tag := reflect.StructTag(raw).Get("gorm")
+	if tag == "" {
+		return settings
+	}
+	for _, part := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(part, ":")
+		key = strings.TrimSpace(strings.ToUpper(key))
+		if key != "" {
+			settings[key] = strings.TrimSpace(value)
+		}
+	}
+	return settings
+}
+
+func isRelationTag(settings map[string]string) bool {
+	for _, key := range []string{"FOREIGNKEY", "REFERENCES", "MANY2MANY", "POLYMORPHIC"} {
+		if _, ok := settings[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) render(models []model) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by sqlormgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg)
+
+	var std, others []string
+	for alias, path := range g.imports {
+		spec := strconv.Quote(path)
+		if packageName(path) != alias {
+			spec = alias + " " + spec
+		}
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	slices.Sort(std)
+	slices.Sort(others)
+
+	buf.WriteString("import (\n")
+	for _, spec := range std {
+		fmt.Fprintf(&buf, "\t%s\n", spec)
+	}
+	if len(std) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, spec := range others {
+		fmt.Fprintf(&buf, "\t%s\n", spec)
+	}
+	buf.WriteString(")\n")
+
+	for _, m := range models {
+		fmt.Fprintf(&buf, "\nvar %sFields = struct {\n", m.Name)
+		for _, c := range m.Columns {
+			fmt.Fprintf(&buf, "\t%s sqlorm.Field[%s]\n", c.Field, c.Type)
+		}
+		buf.WriteString("}{\n")
+		for _, c := range m.Columns {
+			fmt.Fprintf(&buf, "\t%s: sqlorm.NewField[%s](%q),\n", c.Field, c.Type, c.Name)
+		}
+		buf.WriteString("}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
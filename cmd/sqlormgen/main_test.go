@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Generate(t *testing.T) {
+	src, err := generate(config{Dir: "testdata/models", Output: "sqlorm_fields.go"})
+	require.Nil(t, err)
+	code := string(src)
+
+	require.Contains(t, code, "// Code generated by sqlormgen. DO NOT EDIT.")
+	require.Contains(t, code, "package models")
+	require.Contains(t, code, `"github.com/tinh-tinh/sqlorm/v2"`)
+	require.Contains(t, code, `"database/sql"`)
+
+	// Every model is found
+	require.Contains(t, code, "var UserFields = struct")
+	require.Contains(t, code, "var PostFields = struct")
+	require.Contains(t, code, "var ProfileFields = struct")
+	require.NotContains(t, code, "var AddressFields")
+
+	// Columns
+	require.Contains(t, code, `sqlorm.NewField[uint]("id")`)
+	require.Contains(t, code, `sqlorm.NewField[gorm.DeletedAt]("deleted_at")`)
+	require.Contains(t, code, `sqlorm.NewField[int64]("version")`)
+	require.Contains(t, code, `sqlorm.NewField[string]("email")`)
+	require.Contains(t, code, `sqlorm.NewField[string]("name")`)
+	require.Contains(t, code, `sqlorm.NewField[Status]("status")`)
+	require.Contains(t, code, `sqlorm.NewField[*time.Time]("birthday")`)
+	require.Contains(t, code, `sqlorm.NewField[sql.NullInt64]("score")`)
+	require.Contains(t, code, `sqlorm.NewField[[]byte]("avatar")`)
+	require.Contains(t, code, `sqlorm.NewField[[]string]("tags")`)
+	require.Contains(t, code, `sqlorm.NewField[string]("home_street")`)
+	require.Contains(t, code, `sqlorm.NewField[string]("work_city")`)
+	require.Contains(t, code, "HomeStreet")
+	require.Contains(t, code, "WorkCity")
+
+	// Associations, ignored and unexported fields are skipped
+	require.NotContains(t, code, "Posts")
+	require.NotContains(t, code, "Profile sqlorm")
+	require.NotContains(t, code, "Ignored")
+	require.NotContains(t, code, "secret")
+}
+
+func Test_GenerateQualified(t *testing.T) {
+	src, err := generate(config{Dir: "testdata/models", Types: []string{"Post", "User"}, Qualify: true})
+	require.Nil(t, err)
+	code := string(src)
+
+	require.Contains(t, code, `sqlorm.NewField[string]("blog_posts.title")`)
+	require.Contains(t, code, `sqlorm.NewField[string]("users.email")`)
+	require.NotContains(t, code, "ProfileFields")
+}
+
+func Test_GenerateErrors(t *testing.T) {
+	_, err := generate(config{Dir: "testdata/models", Types: []string{"Unknown"}})
+	require.NotNil(t, err)
+
+	dir := t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, "plain.go"), []byte("package plain\n\ntype Plain struct{ Name string }\n"), 0o644)
+	require.Nil(t, err)
+	_, err = generate(config{Dir: dir})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+type Status string
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type User struct {
+	gorm.Model
+	sqlorm.Versioned
+	Email    string `gorm:"type:varchar(255);uniqueIndex"`
+	FullName string `gorm:"column:name"`
+	Status   Status
+	Nickname *string
+	Birthday *time.Time
+	Score    sql.NullInt64
+	Avatar   []byte
+	Home     Address  `gorm:"embedded;embeddedPrefix:home_"`
+	Work     Address  `gorm:"embedded;embeddedPrefix:work_"`
+	Tags     []string `gorm:"serializer:json"`
+	Posts    []Post
+	Profile  *Profile
+	Ignored  string `gorm:"-"`
+	secret   string
+}
+
+type Post struct {
+	ID     uint
+	UserID uint
+	Title  string `gorm:"type:varchar(255)"`
+}
+
+func (Post) TableName() string { return "blog_posts" }
+
+type Profile struct {
+	gorm.Model
+	UserID uint
+}
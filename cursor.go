@@ -0,0 +1,301 @@
+package sqlorm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var ErrInvalidCursor = errors.New("sqlorm: invalid cursor")
+
+type CursorOptions struct {
+	After       string
+	Before      string
+	Limit       int
+	OrderBy     []string
+	Select      []string
+	WithDeleted bool
+	Related     []string
+	Separate    bool
+}
+
+type CursorPage[M any] struct {
+	Items      []*M   `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+type sortKey struct {
+	field *schema.Field
+	desc  bool
+}
+
+// FindPage returns one page of rows using keyset pagination. OrderBy accepts
+// entries like "created_at desc" on columns that cannot be NULL; the primary
+// key is appended as a tiebreaker so the ordering is stable. The returned
+// cursors are opaque and are passed back through After or Before to fetch the
+// adjacent pages. Ordering and raw SQL from a QueryBuilder are rejected.
+func (repo *Repository[M]) FindPage(where Query, opt CursorOptions) (*CursorPage[M], error) {
+	if opt.After != "" && opt.Before != "" {
+		return nil, fmt.Errorf("%w: After and Before are mutually exclusive", ErrInvalidCursor)
+	}
+	if opt.Limit <= 0 {
+		opt.Limit = 20
+	}
+
+	sch, err := repo.schema()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseSortKeys(sch, opt.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	backward := opt.Before != ""
+	cursor := opt.After
+	if backward {
+		cursor = opt.Before
+	}
+
+	var model []*M
+	tx := repo.DB
+	if len(opt.Related) > 0 {
+		for _, key := range opt.Related {
+			if opt.Separate {
+				tx = tx.Preload(key)
+			} else {
+				tx = tx.Joins(key)
+			}
+		}
+	}
+	if opt.Select != nil {
+		tx = tx.Select(opt.Select)
+	}
+	if opt.WithDeleted {
+		tx = tx.Unscoped()
+	}
+
+	tx = repo.applyQuery(tx, where)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if tx.Statement.SQL.Len() > 0 {
+		return nil, fmt.Errorf("sqlorm: FindPage does not support raw sql")
+	}
+	if _, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+		return nil, fmt.Errorf("sqlorm: FindPage orders by CursorOptions.OrderBy only")
+	}
+	if len(tx.Statement.Selects) > 0 {
+		selected := slices.Clone(tx.Statement.Selects)
+		for _, key := range keys {
+			if !slices.Contains(selected, key.field.DBName) {
+				selected = append(selected, key.field.DBName)
+			}
+		}
+		tx.Statement.Selects = selected
+	}
+	groupWhere(tx)
+
+	if cursor != "" {
+		values, err := decodeCursor(cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Where(keysetCondition(keys, values, backward))
+	}
+
+	for _, key := range keys {
+		tx = tx.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: key.field.DBName},
+			Desc:   key.desc != backward,
+		})
+	}
+
+	result := tx.Limit(opt.Limit + 1).Find(&model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	hasMore := len(model) > opt.Limit
+	if hasMore {
+		model = model[:opt.Limit]
+	}
+	if backward {
+		slices.Reverse(model)
+	}
+
+	page := &CursorPage[M]{Items: model}
+	if len(model) == 0 {
+		return page, nil
+	}
+
+	first, err := encodeCursor(repo.DB.Statement.Context, model[0], keys)
+	if err != nil {
+		return nil, err
+	}
+	last, err := encodeCursor(repo.DB.Statement.Context, model[len(model)-1], keys)
+	if err != nil {
+		return nil, err
+	}
+	if backward {
+		page.NextCursor = last
+		if hasMore {
+			page.PrevCursor = first
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = last
+		}
+		if opt.After != "" {
+			page.PrevCursor = first
+		}
+	}
+	return page, nil
+}
+
+func parseSortKeys(sch *schema.Schema, orderBy []string) ([]sortKey, error) {
+	var keys []sortKey
+	for _, order := range orderBy {
+		parts := strings.Fields(order)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, fmt.Errorf("sqlorm: invalid order %q", order)
+		}
+		desc := false
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return nil, fmt.Errorf("sqlorm: invalid order direction %q", parts[1])
+			}
+		}
+
+		column := parts[0]
+		if !isValidColumn(column) {
+			return nil, fmt.Errorf("%w %q in order", ErrInvalidColumn, column)
+		}
+		if table, name, ok := strings.Cut(column, "."); ok {
+			if table != sch.Table {
+				return nil, fmt.Errorf("sqlorm: order column %q does not belong to %s", column, sch.Table)
+			}
+			column = name
+		}
+		field := sch.LookUpField(column)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("sqlorm: unknown order column %q", column)
+		}
+		if isNullable(field) {
+			return nil, fmt.Errorf("sqlorm: order column %q is nullable and cannot be paginated on", column)
+		}
+		keys = append(keys, sortKey{field: field, desc: desc})
+	}
+
+	for _, pk := range sch.PrimaryFields {
+		exists := slices.ContainsFunc(keys, func(key sortKey) bool {
+			return key.field == pk
+		})
+		if !exists {
+			keys = append(keys, sortKey{field: pk})
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("sqlorm: %s has no primary key to paginate on", sch.Table)
+	}
+	return keys, nil
+}
+
+// groupWhere wraps the conditions of tx in one group, so that an OR among
+// them does not escape the keyset condition added after them.
+func groupWhere(tx *gorm.DB) {
+	c, ok := tx.Statement.Clauses["WHERE"]
+	if !ok {
+		return
+	}
+	where, ok := c.Expression.(clause.Where)
+	if !ok || len(where.Exprs) < 2 {
+		return
+	}
+	c.Expression = clause.Where{Exprs: []clause.Expression{clause.And(where.Exprs...)}}
+	tx.Statement.Clauses["WHERE"] = c
+}
+
+// isNullable reports whether the Go type of field can hold NULL. Keyset
+// conditions compare with < and >, which never match NULL.
+func isNullable(field *schema.Field) bool {
+	typ := field.FieldType
+	if typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
+		return true
+	}
+	if typ.Kind() == reflect.Struct {
+		if valid, ok := typ.FieldByName("Valid"); ok && valid.Type.Kind() == reflect.Bool {
+			return true
+		}
+	}
+	return false
+}
+
+// keysetCondition builds (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... honoring
+// the direction of every sort key.
+func keysetCondition(keys []sortKey, values []interface{}, backward bool) clause.Expression {
+	var groups []string
+	var vars []interface{}
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, "? = ?")
+			vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: keys[j].field.DBName}, values[j])
+		}
+		op := " > ?"
+		if key.desc != backward {
+			op = " < ?"
+		}
+		parts = append(parts, "?"+op)
+		vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: key.field.DBName}, values[i])
+		groups = append(groups, "("+strings.Join(parts, " AND ")+")")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(groups, " OR ") + ")", Vars: vars}
+}
+
+func encodeCursor(ctx context.Context, row interface{}, keys []sortKey) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(row))
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i], _ = key.field.ValueOf(ctx, rv)
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor string, keys []sortKey) ([]interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err != nil || len(parts) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		value := reflect.New(key.field.FieldType)
+		if err := json.Unmarshal(parts[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
@@ -0,0 +1,117 @@
+package sqlorm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+func Test_FindPage(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type CursorTodo struct {
+		gorm.Model
+		Name     string `gorm:"type:varchar(255);not null"`
+		Priority int    `gorm:"type:int"`
+	}
+	err := db.AutoMigrate(&CursorTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[CursorTodo]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	var todos []*CursorTodo
+	for i := 0; i < 7; i++ {
+		todos = append(todos, &CursorTodo{Name: fmt.Sprintf("todo %d", i), Priority: i % 3})
+	}
+	_, err = repo.BatchCreate(todos, 10)
+	require.Nil(t, err)
+
+	opt := sqlorm.CursorOptions{Limit: 3, OrderBy: []string{"priority desc"}}
+
+	// Walk forward
+	var seen []string
+	page, err := repo.FindPage(nil, opt)
+	require.Nil(t, err)
+	require.Len(t, page.Items, 3)
+	require.Empty(t, page.PrevCursor)
+	require.NotEmpty(t, page.NextCursor)
+	for page != nil {
+		for _, item := range page.Items {
+			seen = append(seen, item.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opt.After = page.NextCursor
+		page, err = repo.FindPage(nil, opt)
+		require.Nil(t, err)
+	}
+	require.Equal(t, []string{"todo 2", "todo 5", "todo 1", "todo 4", "todo 0", "todo 3", "todo 6"}, seen)
+
+	// Walk backward from the last page
+	require.NotEmpty(t, page.PrevCursor)
+	prev, err := repo.FindPage(nil, sqlorm.CursorOptions{Limit: 3, OrderBy: []string{"priority desc"}, Before: page.PrevCursor})
+	require.Nil(t, err)
+	require.Len(t, prev.Items, 3)
+	require.Equal(t, "todo 4", prev.Items[0].Name)
+	require.Equal(t, "todo 3", prev.Items[2].Name)
+	require.NotEmpty(t, prev.PrevCursor)
+	require.NotEmpty(t, prev.NextCursor)
+
+	// QueryBuilder conditions
+	page, err = repo.FindPage(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 0).Or("priority", 2)
+	}, sqlorm.CursorOptions{Limit: 10, OrderBy: []string{"name"}})
+	require.Nil(t, err)
+	require.Len(t, page.Items, 5)
+	require.Empty(t, page.NextCursor)
+
+	page, err = repo.FindPage(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 0).Or("priority", 2)
+	}, sqlorm.CursorOptions{Limit: 3, OrderBy: []string{"name"}})
+	require.Nil(t, err)
+	require.Len(t, page.Items, 3)
+	page, err = repo.FindPage(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 0).Or("priority", 2)
+	}, sqlorm.CursorOptions{Limit: 3, OrderBy: []string{"name"}, After: page.NextCursor})
+	require.Nil(t, err)
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "todo 5", page.Items[0].Name)
+	require.Equal(t, "todo 6", page.Items[1].Name)
+
+	page, err = repo.FindPage(func(qb *sqlorm.QueryBuilder) {
+		qb.Select("id", "name")
+	}, sqlorm.CursorOptions{Limit: 2, OrderBy: []string{"priority desc"}})
+	require.Nil(t, err)
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "todo 2", page.Items[0].Name)
+	require.NotEmpty(t, page.NextCursor)
+
+	_, err = repo.FindPage(func(qb *sqlorm.QueryBuilder) {
+		qb.Search([]string{"name"}, "todo", sqlorm.FTSOptions{Rank: true})
+	}, sqlorm.CursorOptions{})
+	require.NotNil(t, err)
+
+	_, err = repo.FindPage(func(qb *sqlorm.QueryBuilder) {
+		qb.Raw("SELECT * FROM cursor_todos")
+	}, sqlorm.CursorOptions{})
+	require.NotNil(t, err)
+
+	// Invalid input
+	_, err = repo.FindPage(nil, sqlorm.CursorOptions{After: "not-a-cursor"})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidCursor)
+
+	_, err = repo.FindPage(nil, sqlorm.CursorOptions{OrderBy: []string{"name; DROP TABLE users"}})
+	require.NotNil(t, err)
+
+	_, err = repo.FindPage(nil, sqlorm.CursorOptions{OrderBy: []string{"unknown"}})
+	require.NotNil(t, err)
+
+	_, err = repo.FindPage(nil, sqlorm.CursorOptions{OrderBy: []string{"deleted_at"}})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,159 @@
+package sqlorm
+
+// Field is a typed reference to a model column, usually generated by
+// cmd/sqlormgen. Its condition methods return QueryBuilder functions, so they
+// can be passed directly as a where argument or combined with qb.Where,
+// qb.OrWhere and qb.NotWhere.
+type Field[T any] struct {
+	column string
+}
+
+func NewField[T any](column string) Field[T] {
+	return Field[T]{column: column}
+}
+
+func (f Field[T]) Column() string {
+	return f.column
+}
+
+// Asc and Desc return order entries for FindOptions.Order and
+// QueryChain.OrderBy.
+func (f Field[T]) Asc() string {
+	return f.column + " asc"
+}
+
+func (f Field[T]) Desc() string {
+	return f.column + " desc"
+}
+
+func (f Field[T]) Equal(value T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.Equal(f.column, value)
+	}
+}
+
+func (f Field[T]) NotEqual(value T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.NotEqual(f.column, value)
+	}
+}
+
+func (f Field[T]) In(values ...T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.In(f.column, toAny(values)...)
+	}
+}
+
+func (f Field[T]) NotIn(values ...T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.NotIn(f.column, toAny(values)...)
+	}
+}
+
+func (f Field[T]) MoreThan(value T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.MoreThan(f.column, value)
+	}
+}
+
+func (f Field[T]) MoreThanOrEqual(value T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.MoreThanOrEqual(f.column, value)
+	}
+}
+
+func (f Field[T]) LessThan(value T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.LessThan(f.column, value)
+	}
+}
+
+func (f Field[T]) LessThanOrEqual(value T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.LessThanOrEqual(f.column, value)
+	}
+}
+
+func (f Field[T]) Between(start T, end T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.Between(f.column, start, end)
+	}
+}
+
+func (f Field[T]) NotBetween(start T, end T) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.NotBetween(f.column, start, end)
+	}
+}
+
+func (f Field[T]) IsNull() func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.IsNull(f.column)
+	}
+}
+
+func (f Field[T]) IsNotNull() func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.IsNotNull(f.column)
+	}
+}
+
+// The pattern methods take a string whatever T is, as the column is compared
+// as text.
+func (f Field[T]) Like(pattern string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.Like(f.column, pattern)
+	}
+}
+
+func (f Field[T]) NotLike(pattern string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.NotLike(f.column, pattern)
+	}
+}
+
+func (f Field[T]) ILike(pattern string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.ILike(f.column, pattern)
+	}
+}
+
+func (f Field[T]) NotILike(pattern string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.NotILike(f.column, pattern)
+	}
+}
+
+func (f Field[T]) StartsWith(prefix string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.StartsWith(f.column, prefix)
+	}
+}
+
+func (f Field[T]) EndsWith(suffix string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.EndsWith(f.column, suffix)
+	}
+}
+
+func (f Field[T]) Contains(substr string) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.Contains(f.column, substr)
+	}
+}
+
+// EqualField compares the column with another column, e.g. to correlate a
+// subquery.
+func EqualField[T any](f Field[T], other Field[T]) func(qb *QueryBuilder) {
+	return func(qb *QueryBuilder) {
+		qb.EqualColumn(f.column, other.column)
+	}
+}
+
+func toAny[T any](values []T) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
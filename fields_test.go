@@ -0,0 +1,75 @@
+package sqlorm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+type FieldUser struct {
+	gorm.Model
+	Email string `gorm:"type:varchar(255)"`
+	Age   int
+}
+
+var FieldUserFields = struct {
+	ID    sqlorm.Field[uint]
+	Email sqlorm.Field[string]
+	Age   sqlorm.Field[int]
+}{
+	ID:    sqlorm.NewField[uint]("field_users.id"),
+	Email: sqlorm.NewField[string]("field_users.email"),
+	Age:   sqlorm.NewField[int]("field_users.age"),
+}
+
+func Test_Field(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&FieldUser{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[FieldUser]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*FieldUser{
+		{Email: "alice@example.com", Age: 20},
+		{Email: "bob@example.com", Age: 30},
+		{Email: "carol@test.com", Age: 40},
+	}, 3)
+	require.Nil(t, err)
+
+	fields := FieldUserFields
+	require.Equal(t, "field_users.email", fields.Email.Column())
+	require.Equal(t, "field_users.age desc", fields.Age.Desc())
+
+	// As a where argument
+	users, err := repo.FindAll(fields.Email.EndsWith("@example.com"), sqlorm.FindOptions{
+		Order: []string{fields.Age.Desc()},
+	})
+	require.Nil(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, "bob@example.com", users[0].Email)
+
+	// Combined inside a QueryBuilder
+	users, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Where(fields.Age.MoreThan(25)).
+			Where(func(g *sqlorm.QueryBuilder) {
+				g.Where(fields.Email.ILike("BOB%")).OrWhere(fields.Email.Contains("test"))
+			})
+	}, sqlorm.FindOptions{Order: []string{fields.Age.Asc()}})
+	require.Nil(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, 30, users[0].Age)
+	require.Equal(t, 40, users[1].Age)
+
+	count, err := repo.Count(fields.Age.In(20, 40))
+	require.Nil(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = repo.Query().Where(fields.Age.Between(25, 45)).Where(fields.Email.NotEqual("carol@test.com")).Count()
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+}
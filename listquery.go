@@ -0,0 +1,322 @@
+package sqlorm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common/exception"
+	"gorm.io/gorm/schema"
+)
+
+var ErrInvalidListQuery = errors.New("sqlorm: invalid list query")
+
+const DefaultMaxPerPage = 100
+
+const (
+	FilterEq      = "eq"
+	FilterNe      = "ne"
+	FilterGt      = "gt"
+	FilterGte     = "gte"
+	FilterLt      = "lt"
+	FilterLte     = "lte"
+	FilterLike    = "like"
+	FilterILike   = "ilike"
+	FilterIn      = "in"
+	FilterNin     = "nin"
+	FilterBetween = "between"
+	FilterNull    = "null"
+)
+
+var filterOperators = []string{
+	FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterLike,
+	FilterILike, FilterIn, FilterNin, FilterBetween, FilterNull,
+}
+
+// AllowList lists the fields of a model a client may filter and sort on.
+// Fields are named by their column or struct field name.
+type AllowList struct {
+	// Filter maps a field to its allowed operators; an empty list allows
+	// every operator.
+	Filter      map[string][]string
+	Sort        []string
+	DefaultSort []string
+	// MaxPerPage caps perPage, DefaultMaxPerPage when zero.
+	MaxPerPage int
+}
+
+type ListQuery struct {
+	Where   func(qb *QueryBuilder)
+	Options FindOptions
+	Page    int
+	PerPage int
+}
+
+// ListQueryError reports a query parameter rejected by ParseListQuery. It is
+// meant to be returned to the client as a 400 Bad Request.
+type ListQueryError struct {
+	Param  string
+	Reason string
+}
+
+func (e *ListQueryError) Error() string {
+	return fmt.Sprintf("invalid query parameter %s: %s", e.Param, e.Reason)
+}
+
+func (e *ListQueryError) Unwrap() error {
+	return ErrInvalidListQuery
+}
+
+func (e *ListQueryError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e *ListQueryError) Exception() exception.Http {
+	return exception.BadRequest(e.Error())
+}
+
+// ParseListQuery turns query parameters like
+//
+//	?filter[name][ilike]=jo%&filter[age][gte]=18&sort=-created_at,name&page=2&perPage=20
+//
+// into a where function and FindOptions for the model of repo, whose fields
+// are resolved with the naming strategy of its DB. A filter without operator
+// means eq; in, nin and between take comma separated values and null takes
+// true or false. Parameters outside the allow list are rejected with a
+// *ListQueryError.
+func ParseListQuery[M any](repo *Repository[M], r *http.Request, allow AllowList) (*ListQuery, error) {
+	sch, err := repo.schema()
+	if err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var conditions []func(qb *QueryBuilder)
+	for _, key := range keys {
+		name, op, ok := parseFilterKey(key)
+		if !ok {
+			continue
+		}
+		allowed, ok := allow.Filter[name]
+		if !ok {
+			return nil, &ListQueryError{Param: key, Reason: "filtering on " + name + " is not allowed"}
+		}
+		if !slices.Contains(filterOperators, op) {
+			return nil, &ListQueryError{Param: key, Reason: "unknown operator " + op}
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, op) {
+			return nil, &ListQueryError{Param: key, Reason: "operator " + op + " is not allowed on " + name}
+		}
+		field := sch.LookUpField(name)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("%w %q in allow list", ErrInvalidColumn, name)
+		}
+		for _, raw := range params[key] {
+			condition, err := filterCondition(field, op, raw)
+			if err != nil {
+				return nil, &ListQueryError{Param: key, Reason: err.Error()}
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+
+	query := &ListQuery{
+		Where: func(qb *QueryBuilder) {
+			for _, condition := range conditions {
+				condition(qb)
+			}
+		},
+	}
+
+	sorts := allow.DefaultSort
+	if params.Has("sort") {
+		sorts = strings.Split(params.Get("sort"), ",")
+	}
+	for _, sort := range sorts {
+		sort = strings.TrimSpace(sort)
+		if sort == "" {
+			continue
+		}
+		direction := "asc"
+		switch sort[0] {
+		case '-':
+			direction = "desc"
+			sort = sort[1:]
+		case '+':
+			sort = sort[1:]
+		}
+		if !slices.Contains(allow.Sort, sort) {
+			return nil, &ListQueryError{Param: "sort", Reason: "sorting on " + sort + " is not allowed"}
+		}
+		field := sch.LookUpField(sort)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("%w %q in allow list", ErrInvalidColumn, sort)
+		}
+		query.Options.Order = append(query.Options.Order, field.DBName+" "+direction)
+	}
+
+	query.Page, err = positiveParam(params, "page", 1)
+	if err != nil {
+		return nil, err
+	}
+	query.PerPage, err = positiveParam(params, "perPage", DefaultPerPage)
+	if err != nil {
+		return nil, err
+	}
+	maxPerPage := allow.MaxPerPage
+	if maxPerPage <= 0 {
+		maxPerPage = DefaultMaxPerPage
+	}
+	if query.PerPage > maxPerPage {
+		return nil, &ListQueryError{Param: "perPage", Reason: fmt.Sprintf("must not exceed %d", maxPerPage)}
+	}
+	query.Options.Limit = query.PerPage
+	query.Options.Offset = (query.Page - 1) * query.PerPage
+
+	return query, nil
+}
+
+// parseFilterKey splits filter[name] and filter[name][op].
+func parseFilterKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, "filter[")
+	if !ok {
+		return "", "", false
+	}
+	rest, ok = strings.CutSuffix(rest, "]")
+	if !ok {
+		return "", "", false
+	}
+	name, op, found := strings.Cut(rest, "][")
+	if !found {
+		op = FilterEq
+	}
+	return name, strings.ToLower(op), true
+}
+
+func filterCondition(field *schema.Field, op string, raw string) (func(qb *QueryBuilder), error) {
+	column := field.DBName
+	switch op {
+	case FilterLike:
+		return func(qb *QueryBuilder) { qb.Like(column, raw) }, nil
+	case FilterILike:
+		return func(qb *QueryBuilder) { qb.ILike(column, raw) }, nil
+	case FilterNull:
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+		if isNull {
+			return func(qb *QueryBuilder) { qb.IsNull(column) }, nil
+		}
+		return func(qb *QueryBuilder) { qb.IsNotNull(column) }, nil
+	case FilterIn, FilterNin, FilterBetween:
+		parts := strings.Split(raw, ",")
+		if op == FilterBetween && len(parts) != 2 {
+			return nil, errors.New("expected two comma separated values")
+		}
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			value, err := filterValue(field, part)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		switch op {
+		case FilterIn:
+			return func(qb *QueryBuilder) { qb.In(column, values...) }, nil
+		case FilterNin:
+			return func(qb *QueryBuilder) { qb.NotIn(column, values...) }, nil
+		default:
+			return func(qb *QueryBuilder) { qb.Between(column, values[0], values[1]) }, nil
+		}
+	}
+
+	value, err := filterValue(field, raw)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case FilterNe:
+		return func(qb *QueryBuilder) { qb.NotEqual(column, value) }, nil
+	case FilterGt:
+		return func(qb *QueryBuilder) { qb.MoreThan(column, value) }, nil
+	case FilterGte:
+		return func(qb *QueryBuilder) { qb.MoreThanOrEqual(column, value) }, nil
+	case FilterLt:
+		return func(qb *QueryBuilder) { qb.LessThan(column, value) }, nil
+	case FilterLte:
+		return func(qb *QueryBuilder) { qb.LessThanOrEqual(column, value) }, nil
+	default:
+		return func(qb *QueryBuilder) { qb.Equal(column, value) }, nil
+	}
+}
+
+// filterValue converts a query string value to the kind of the field, so
+// that malformed numbers, booleans and times are rejected before querying.
+func filterValue(field *schema.Field, raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	typ := field.FieldType
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if value, err := time.Parse(layout, raw); err == nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid time %q", raw)
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return value, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		return value, nil
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", raw)
+		}
+		return value, nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", raw)
+		}
+		return value, nil
+	}
+	return raw, nil
+}
+
+func positiveParam(params map[string][]string, name string, fallback int) (int, error) {
+	values := params[name]
+	if len(values) == 0 || values[0] == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(values[0])
+	if err != nil || value < 1 {
+		return 0, &ListQueryError{Param: name, Reason: "expected a positive integer"}
+	}
+	return value, nil
+}
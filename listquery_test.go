@@ -0,0 +1,130 @@
+package sqlorm_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type ListMember struct {
+	gorm.Model
+	Name   string `gorm:"type:varchar(255)"`
+	Age    int
+	Active bool
+}
+
+var listMemberAllow = sqlorm.AllowList{
+	Filter: map[string][]string{
+		"name":   {sqlorm.FilterEq, sqlorm.FilterILike},
+		"age":    nil,
+		"active": {sqlorm.FilterEq},
+	},
+	Sort:        []string{"name", "age", "created_at"},
+	DefaultSort: []string{"name"},
+	MaxPerPage:  50,
+}
+
+func Test_ParseListQuery(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&ListMember{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[ListMember]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*ListMember{
+		{Name: "john", Age: 17, Active: true},
+		{Name: "joan", Age: 25, Active: true},
+		{Name: "josh", Age: 32, Active: false},
+		{Name: "mary", Age: 40, Active: true},
+	}, 4)
+	require.Nil(t, err)
+
+	find := func(url string) []*ListMember {
+		query, err := sqlorm.ParseListQuery(&repo, httptest.NewRequest(http.MethodGet, url, nil), listMemberAllow)
+		require.Nil(t, err)
+		members, err := repo.FindAll(query.Where, query.Options)
+		require.Nil(t, err)
+		return members
+	}
+	names := func(members []*ListMember) []string {
+		var result []string
+		for _, member := range members {
+			result = append(result, member.Name)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"joan", "john", "josh", "mary"}, names(find("/members")))
+	require.Equal(t, []string{"josh", "joan"}, names(find("/members?filter[name][ilike]=JO%25&filter[age][gte]=18&sort=-age")))
+	require.Equal(t, []string{"john", "mary"}, names(find("/members?filter[age][in]=17,40")))
+	require.Equal(t, []string{"joan", "john", "mary"}, names(find("/members?filter[active]=true")))
+	require.Equal(t, []string{"joan", "josh"}, names(find("/members?filter[age][between]=20,35&sort=name")))
+	require.Equal(t, []string{"josh"}, names(find("/members?sort=age&page=2&perPage=2&filter[age][lt]=40")))
+
+	// Page and PerPage can be passed to Paginate
+	query, err := sqlorm.ParseListQuery(&repo, httptest.NewRequest(http.MethodGet, "/members?page=2&perPage=3", nil), listMemberAllow)
+	require.Nil(t, err)
+	require.Equal(t, 2, query.Page)
+	require.Equal(t, 3, query.PerPage)
+	page, err := repo.Paginate(query.Where, query.Page, query.PerPage, query.Options)
+	require.Nil(t, err)
+	require.Equal(t, int64(4), page.Total)
+	require.Equal(t, []string{"mary"}, names(page.Items))
+}
+
+func Test_ParseListQueryErrors(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DisableAutomaticPing: true})
+	require.Nil(t, err)
+	repo := sqlorm.Repository[ListMember]{DB: db}
+
+	for _, url := range []string{
+		"/members?filter[deleted_at][null]=true",
+		"/members?filter[name][gt]=a",
+		"/members?filter[name][regex]=a",
+		"/members?filter[age]=abc",
+		"/members?filter[age][between]=1",
+		"/members?filter[active]=maybe",
+		"/members?sort=-active",
+		"/members?page=-1",
+		"/members?perPage=51",
+	} {
+		_, err := sqlorm.ParseListQuery(&repo, httptest.NewRequest(http.MethodGet, url, nil), listMemberAllow)
+		require.ErrorIs(t, err, sqlorm.ErrInvalidListQuery, url)
+
+		var listErr *sqlorm.ListQueryError
+		require.ErrorAs(t, err, &listErr)
+		require.Equal(t, http.StatusBadRequest, listErr.StatusCode())
+		require.Equal(t, http.StatusBadRequest, listErr.Exception().Status)
+	}
+
+	// Fields of the allow list must exist on the model
+	_, err = sqlorm.ParseListQuery(&repo, httptest.NewRequest(http.MethodGet, "/members?filter[unknown]=1", nil), sqlorm.AllowList{
+		Filter: map[string][]string{"unknown": nil},
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+}
+
+func Test_ParseListQueryNamingStrategy(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+		DisableAutomaticPing: true,
+		NamingStrategy:       schema.NamingStrategy{NameReplacer: strings.NewReplacer("Age", "Years")},
+	})
+	require.Nil(t, err)
+	repo := sqlorm.Repository[ListMember]{DB: db}
+
+	query, err := sqlorm.ParseListQuery(&repo, httptest.NewRequest(http.MethodGet, "/members?sort=-Age", nil), sqlorm.AllowList{
+		Sort: []string{"Age"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"years desc"}, query.Options.Order)
+}
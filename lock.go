@@ -0,0 +1,44 @@
+package sqlorm
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrLockOutsideTransaction = errors.New("sqlorm: row locks require a transaction")
+	// ErrLockAggregate is returned when Lock is set on a count or aggregate
+	// query, which cannot lock rows.
+	ErrLockAggregate = errors.New("sqlorm: aggregate queries cannot lock rows")
+)
+
+const (
+	LockForUpdate      = clause.LockingStrengthUpdate
+	LockForShare       = clause.LockingStrengthShare
+	LockForNoKeyUpdate = "NO KEY UPDATE"
+	LockForKeyShare    = "KEY SHARE"
+
+	LockNoWait     = clause.LockingOptionsNoWait
+	LockSkipLocked = clause.LockingOptionsSkipLocked
+)
+
+type Lock struct {
+	Strength string
+	Options  string
+}
+
+func applyLock(tx *gorm.DB, lock *Lock) *gorm.DB {
+	if lock == nil {
+		return tx
+	}
+	if _, ok := tx.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		return withError(tx, ErrLockOutsideTransaction)
+	}
+	strength := lock.Strength
+	if strength == "" {
+		strength = LockForUpdate
+	}
+	return tx.Clauses(clause.Locking{Strength: strength, Options: lock.Options})
+}
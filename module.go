@@ -37,6 +37,11 @@ func ForRoot(config Config) core.Modules {
 			Value: conn,
 		})
 		sqlModule.Export(ConnectDB)
+		sqlModule.NewProvider(core.ProviderOptions{
+			Name:  UnitOfWorkProvider,
+			Value: NewUnitOfWork(conn),
+		})
+		sqlModule.Export(UnitOfWorkProvider)
 
 		return sqlModule
 	}
@@ -54,6 +59,11 @@ func ForRootFactory(factory ConfigFactory) core.Modules {
 			Value: conn,
 		})
 		sqlModule.Export(ConnectDB)
+		sqlModule.NewProvider(core.ProviderOptions{
+			Name:  UnitOfWorkProvider,
+			Value: NewUnitOfWork(conn),
+		})
+		sqlModule.Export(UnitOfWorkProvider)
 
 		return sqlModule
 	}
@@ -101,9 +111,12 @@ func ForFeature(val ...RepoCommon) core.Modules {
 					if connect != nil {
 						v.SetDB(connect)
 					}
+					if uow, ok := param[1].(*UnitOfWork); ok {
+						uow.Register(v)
+					}
 					return v
 				},
-				Inject: []core.Provide{ConnectDB},
+				Inject: []core.Provide{ConnectDB, UnitOfWorkProvider},
 			})
 			modelModule.Export(name)
 		}
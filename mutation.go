@@ -1,9 +1,22 @@
 package sqlorm
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
 
 func (repo *Repository[M]) Create(val interface{}) (*M, error) {
 	input := MapOne[M](val)
+	if field := repo.versionField(); field != nil {
+		repo.initVersion(field, input)
+	}
 	result := repo.DB.Create(input)
 	if result.Error != nil {
 		return nil, result.Error
@@ -13,6 +26,9 @@ func (repo *Repository[M]) Create(val interface{}) (*M, error) {
 
 func (repo *Repository[M]) BatchCreate(val interface{}, size int) ([]*M, error) {
 	input := MapMany[M](val)
+	if field := repo.versionField(); field != nil {
+		repo.initVersion(field, input...)
+	}
 	result := repo.DB.CreateInBatches(input, size)
 	if result.Error != nil {
 		return nil, result.Error
@@ -20,34 +36,269 @@ func (repo *Repository[M]) BatchCreate(val interface{}, size int) ([]*M, error)
 	return input, nil
 }
 
-func (repo *Repository[M]) UpdateOne(where interface{}, val interface{}) (*M, error) {
-	var record M
+// ErrConflictSkipped is returned by Upsert when DoNothing skipped the insert
+// and the existing row could not be selected, as no conflict column was
+// given.
+var ErrConflictSkipped = errors.New("sqlorm: upsert skipped on conflict")
+
+type UpsertOptions struct {
+	// ConflictColumns are the unique columns that detect a conflict. The
+	// primary key is used when empty.
+	ConflictColumns []string
+	// UpdateColumns are overwritten with the new values on conflict. All
+	// columns are updated when empty.
+	UpdateColumns []string
+	// DoNothing keeps the existing row on conflict; Upsert and BatchUpsert
+	// then select it again to return it.
+	DoNothing bool
+}
+
+func (repo *Repository[M]) Upsert(val interface{}, options ...UpsertOptions) (*M, error) {
 	input := MapOne[M](val)
-	result := repo.DB.Model(&record).Where(where).Updates(input)
+	if field := repo.versionField(); field != nil {
+		repo.initVersion(field, input)
+	}
+	onConflict, err := repo.upsertClause(options...)
+	if err != nil {
+		return nil, err
+	}
+	result := repo.DB.Clauses(onConflict, clause.Returning{}).Create(input)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	if onConflict.DoNothing && result.RowsAffected == 0 {
+		matched, err := repo.reloadConflicts(onConflict, []*M{input})
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			return nil, ErrConflictSkipped
+		}
+	}
 	return input, nil
 }
 
-func (repo *Repository[M]) UpdateByID(id any, val interface{}) (*M, error) {
-	return repo.UpdateOne(map[string]any{"id": id}, val)
+func (repo *Repository[M]) BatchUpsert(val interface{}, size int, options ...UpsertOptions) ([]*M, error) {
+	input := MapMany[M](val)
+	if field := repo.versionField(); field != nil {
+		repo.initVersion(field, input...)
+	}
+	onConflict, err := repo.upsertClause(options...)
+	if err != nil {
+		return nil, err
+	}
+	result := repo.DB.Clauses(onConflict, clause.Returning{}).CreateInBatches(input, size)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if onConflict.DoNothing && result.RowsAffected < int64(len(input)) {
+		for start := 0; start < len(input); start += max(size, 1) {
+			end := min(start+max(size, 1), len(input))
+			if _, err := repo.reloadConflicts(onConflict, input[start:end]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return input, nil
 }
 
-func (repo *Repository[M]) UpdateMany(where interface{}, val interface{}) error {
-	var model M
+// reloadConflicts replaces inputs with the stored rows sharing their conflict
+// columns. With DO NOTHING, RETURNING yields no row for a skipped input and
+// the returned rows can no longer be matched to the inputs by position.
+// Without conflict columns the rows are matched on the primary key, and
+// inputs without one are left as they are. It reports whether every input
+// was matched.
+func (repo *Repository[M]) reloadConflicts(onConflict clause.OnConflict, inputs []*M) (bool, error) {
+	sch, err := repo.schema()
+	if err != nil {
+		return false, err
+	}
+	fields := sch.PrimaryFields
+	if len(onConflict.Columns) > 0 {
+		fields = make([]*schema.Field, len(onConflict.Columns))
+		for i, column := range onConflict.Columns {
+			fields[i] = sch.LookUpField(column.Name)
+			if fields[i] == nil {
+				return false, fmt.Errorf("%w %q in conflict columns", ErrInvalidColumn, column.Name)
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	ctx := repo.DB.Statement.Context
+	key := func(record *M) ([]interface{}, bool) {
+		rv := reflect.ValueOf(record).Elem()
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			value, zero := field.ValueOf(ctx, rv)
+			if zero && field.PrimaryKey {
+				return nil, false
+			}
+			if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() {
+				value = v.Elem().Interface()
+			}
+			values[i] = value
+		}
+		return values, true
+	}
+
+	var keys [][]interface{}
+	for _, input := range inputs {
+		if values, ok := key(input); ok {
+			keys = append(keys, values)
+		}
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+	columns := make([]interface{}, len(fields))
+	for i, field := range fields {
+		columns[i] = clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+	}
+
+	var stored []*M
+	result := repo.DB.Unscoped().
+		Where("("+placeholders(len(fields))+") IN ?", append(columns, keys)...).
+		Find(&stored)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	byKey := make(map[string]*M, len(stored))
+	for _, record := range stored {
+		values, _ := key(record)
+		byKey[fmt.Sprintf("%#v", values)] = record
+	}
+	matched := true
+	for _, input := range inputs {
+		values, ok := key(input)
+		if !ok {
+			matched = false
+			continue
+		}
+		record, ok := byKey[fmt.Sprintf("%#v", values)]
+		if !ok {
+			matched = false
+			continue
+		}
+		*input = *record
+	}
+	return matched, nil
+}
+
+func (repo *Repository[M]) upsertClause(options ...UpsertOptions) (clause.OnConflict, error) {
+	var opt UpsertOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+
+	onConflict := clause.OnConflict{DoNothing: opt.DoNothing}
+	for _, column := range opt.ConflictColumns {
+		if !isValidColumn(column) {
+			return onConflict, fmt.Errorf("%w %q in conflict columns", ErrInvalidColumn, column)
+		}
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	if opt.DoNothing {
+		return onConflict, nil
+	}
+
+	sch, err := repo.schema()
+	if err != nil {
+		return onConflict, err
+	}
+	if len(onConflict.Columns) == 0 {
+		for _, name := range sch.PrimaryFieldDBNames {
+			onConflict.Columns = append(onConflict.Columns, clause.Column{Name: name})
+		}
+	}
+	for _, column := range opt.UpdateColumns {
+		if !isValidColumn(column) {
+			return onConflict, fmt.Errorf("%w %q in update columns", ErrInvalidColumn, column)
+		}
+	}
+
+	version := versionField(sch)
+	if version == nil {
+		if len(opt.UpdateColumns) == 0 {
+			onConflict.UpdateAll = true
+		} else {
+			onConflict.DoUpdates = clause.AssignmentColumns(opt.UpdateColumns)
+		}
+		return onConflict, nil
+	}
+
+	// The version must be bumped rather than overwritten by the inserted row.
+	columns := opt.UpdateColumns
+	if len(columns) == 0 {
+		for _, field := range sch.Fields {
+			if field.DBName != "" && field.Updatable && !field.PrimaryKey && field.AutoCreateTime == 0 && field != version {
+				columns = append(columns, field.DBName)
+			}
+		}
+	}
+	columns = slices.DeleteFunc(slices.Clone(columns), func(column string) bool {
+		return column == version.DBName
+	})
+	onConflict.DoUpdates = append(clause.AssignmentColumns(columns), clause.Assignment{
+		Column: clause.Column{Name: version.DBName},
+		Value:  gorm.Expr("?.? + 1", clause.Table{Name: sch.Table}, clause.Column{Name: version.DBName}),
+	})
+	return onConflict, nil
+}
+
+// UpdateOne updates the rows matching where and returns the updated row as
+// stored, or gorm.ErrRecordNotFound when nothing matched.
+func (repo *Repository[M]) UpdateOne(where interface{}, val interface{}) (*M, error) {
 	input := MapOne[M](val)
-	tx := repo.DB.Model(&model)
-	if where != nil {
+	var version clause.Expression
+
+	field := repo.versionField()
+	if field != nil {
+		var err error
+		version, err = repo.lockVersion(field, val, input)
+		if err != nil {
+			return nil, err
+		}
+	}
+	records, affected, err := repo.updateReturning(func(tx *gorm.DB) *gorm.DB {
 		tx = tx.Where(where)
-	} else {
-		tx = tx.Where("1 = 1")
+		if version != nil {
+			tx = tx.Where(version)
+		}
+		return tx
+	}, input)
+	if err != nil {
+		return nil, err
 	}
-	result := tx.Updates(input)
-	if result.Error != nil {
-		return result.Error
+	if affected == 0 || len(records) == 0 {
+		if field != nil {
+			return nil, ErrStaleObject
+		}
+		return nil, gorm.ErrRecordNotFound
 	}
-	return nil
+	return records[0], nil
+}
+
+func (repo *Repository[M]) UpdateByID(id any, val interface{}) (*M, error) {
+	return repo.UpdateOne(map[string]any{"id": id}, val)
+}
+
+// UpdateMany updates every row matching where and returns the updated rows
+// as stored with the number of rows affected.
+func (repo *Repository[M]) UpdateMany(where interface{}, val interface{}) ([]*M, int64, error) {
+	input := MapOne[M](val)
+	var values interface{} = input
+	if field := repo.versionField(); field != nil {
+		values = repo.bumpVersion(field, input)
+	}
+	return repo.updateReturning(func(tx *gorm.DB) *gorm.DB {
+		if where != nil {
+			return tx.Where(where)
+		}
+		return tx.Where("1 = 1")
+	}, values)
 }
 
 func (repo *Repository[M]) DeleteOne(where interface{}, isForceDelete ...bool) error {
@@ -106,7 +357,11 @@ func (repo *Repository[M]) Increment(id any, field string, value int) error {
 		return err
 	}
 
-	result := repo.DB.Model(record).Update(field, gorm.Expr(field+" + ?", value))
+	values := map[string]interface{}{field: gorm.Expr(field+" + ?", value)}
+	if version := repo.versionField(); version != nil {
+		values[version.DBName] = versionIncrement(version)
+	}
+	result := repo.DB.Model(record).Updates(values)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -119,7 +374,11 @@ func (repo *Repository[M]) Decrement(id any, field string, value int) error {
 		return err
 	}
 
-	result := repo.DB.Model(record).Update(field, gorm.Expr(field+" - ?", value))
+	values := map[string]interface{}{field: gorm.Expr(field+" - ?", value)}
+	if version := repo.versionField(); version != nil {
+		values[version.DBName] = versionIncrement(version)
+	}
+	result := repo.DB.Model(record).Updates(values)
 	if result.Error != nil {
 		return result.Error
 	}
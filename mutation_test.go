@@ -115,14 +115,132 @@ func Test_UpdateMany(t *testing.T) {
 
 	repo := sqlorm.Repository[Todo]{DB: db}
 	require.NotPanics(t, func() {
-		err := repo.UpdateMany(map[string]interface{}{"name": "haha"}, map[string]interface{}{"name": "lulu"})
+		_, _, err := repo.UpdateMany(map[string]interface{}{"name": "haha"}, map[string]interface{}{"name": "lulu"})
 		require.Nil(t, err)
 
-		err = repo.UpdateMany(nil, map[string]interface{}{"name": "mahula"})
+		_, _, err = repo.UpdateMany(nil, map[string]interface{}{"name": "mahula"})
 		require.Nil(t, err)
 	})
 }
 
+func Test_UpdateReturning(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type Ticket struct {
+		gorm.Model
+		Title  string `gorm:"type:varchar(255);not null"`
+		Status string `gorm:"type:varchar(50)"`
+	}
+	err := db.AutoMigrate(&Ticket{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[Ticket]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*Ticket{
+		{Title: "login fails", Status: "open"},
+		{Title: "slow search", Status: "open"},
+		{Title: "typo", Status: "closed"},
+	}, 3)
+	require.Nil(t, err)
+	created, err := repo.FindOne(map[string]any{"title": "login fails"})
+	require.Nil(t, err)
+
+	// Untouched columns come back as stored
+	updated, err := repo.UpdateByID(created.ID, map[string]any{"Status": "in_progress"})
+	require.Nil(t, err)
+	require.Equal(t, created.ID, updated.ID)
+	require.Equal(t, "login fails", updated.Title)
+	require.Equal(t, "in_progress", updated.Status)
+	require.False(t, updated.CreatedAt.IsZero())
+	require.True(t, updated.UpdatedAt.After(created.UpdatedAt))
+
+	_, err = repo.UpdateOne(map[string]any{"title": "unknown"}, map[string]any{"Status": "closed"})
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	tickets, affected, err := repo.UpdateMany(map[string]any{"status": "open"}, map[string]any{"Status": "closed"})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), affected)
+	require.Len(t, tickets, 1)
+	require.Equal(t, "slow search", tickets[0].Title)
+	require.Equal(t, "closed", tickets[0].Status)
+	require.NotZero(t, tickets[0].ID)
+
+	tickets, affected, err = repo.UpdateMany(map[string]any{"status": "open"}, map[string]any{"Status": "closed"})
+	require.Nil(t, err)
+	require.Zero(t, affected)
+	require.Empty(t, tickets)
+
+	// Dialects without RETURNING select the updated rows again
+	noReturning, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:              "host=localhost user=postgres password=postgres dbname=test port=5432 sslmode=disable TimeZone=Asia/Shanghai",
+		WithoutReturning: true,
+	}), &gorm.Config{})
+	require.Nil(t, err)
+	fallback := sqlorm.Repository[Ticket]{DB: noReturning}
+
+	updated, err = fallback.UpdateByID(created.ID, map[string]any{"Status": "open"})
+	require.Nil(t, err)
+	require.Equal(t, created.ID, updated.ID)
+	require.Equal(t, "login fails", updated.Title)
+	require.Equal(t, "open", updated.Status)
+
+	tickets, affected, err = fallback.UpdateMany(map[string]any{"status": "closed"}, map[string]any{"Status": "archived"})
+	require.Nil(t, err)
+	require.Equal(t, int64(2), affected)
+	require.Len(t, tickets, 2)
+	for _, ticket := range tickets {
+		require.Equal(t, "archived", ticket.Status)
+		require.NotEmpty(t, ticket.Title)
+	}
+
+	tickets, affected, err = fallback.UpdateMany(map[string]any{"status": "closed"}, map[string]any{"Status": "archived"})
+	require.Nil(t, err)
+	require.Zero(t, affected)
+	require.Empty(t, tickets)
+}
+
+type HookedTicket struct {
+	gorm.Model
+	Title  string `gorm:"type:varchar(255)"`
+	Editor string `gorm:"type:varchar(255)"`
+}
+
+func (h *HookedTicket) BeforeUpdate(tx *gorm.DB) error {
+	tx.Statement.SetColumn("Editor", "hook")
+	return nil
+}
+
+func Test_UpdateHooks(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&HookedTicket{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[HookedTicket]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	created, err := repo.Create(&HookedTicket{Title: "first"})
+	require.Nil(t, err)
+	_, err = repo.Create(&HookedTicket{Title: "second"})
+	require.Nil(t, err)
+
+	updated, err := repo.UpdateByID(created.ID, &HookedTicket{Title: "renamed"})
+	require.Nil(t, err)
+	require.Equal(t, "renamed", updated.Title)
+	require.Equal(t, "hook", updated.Editor)
+
+	tickets, affected, err := repo.UpdateMany(nil, map[string]any{"Editor": "bulk"})
+	require.Nil(t, err)
+	require.Equal(t, int64(2), affected)
+	require.Len(t, tickets, 2)
+	for _, ticket := range tickets {
+		require.Equal(t, "hook", ticket.Editor)
+	}
+}
+
 func Test_Delete(t *testing.T) {
 	db := prepareBeforeTest(t)
 
@@ -293,3 +411,210 @@ func Test_Decrement(t *testing.T) {
 	err = repo.Decrement(fmt.Sprintf("%d", first.ID), "Kafka", 1)
 	require.NotNil(t, err)
 }
+
+func Test_Upsert(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type UpsertUser struct {
+		gorm.Model
+		Email  string `gorm:"type:varchar(255);uniqueIndex"`
+		Name   string `gorm:"type:varchar(255)"`
+		Status string `gorm:"type:varchar(50);default:'pending'"`
+	}
+	err := db.AutoMigrate(&UpsertUser{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[UpsertUser]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	type UpsertInput struct {
+		Email string
+		Name  string
+	}
+
+	created, err := repo.Upsert(&UpsertInput{Email: "a@example.com", Name: "A"}, sqlorm.UpsertOptions{
+		ConflictColumns: []string{"email"},
+		UpdateColumns:   []string{"name"},
+	})
+	require.Nil(t, err)
+	require.NotZero(t, created.ID)
+	require.Equal(t, "pending", created.Status)
+
+	updated, err := repo.Upsert(&UpsertInput{Email: "a@example.com", Name: "A2"}, sqlorm.UpsertOptions{
+		ConflictColumns: []string{"email"},
+		UpdateColumns:   []string{"name"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, created.ID, updated.ID)
+	require.Equal(t, "A2", updated.Name)
+
+	count, err := repo.Count(nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Do nothing keeps and returns the existing row
+	kept, err := repo.Upsert(&UpsertInput{Email: "a@example.com", Name: "ignored"}, sqlorm.UpsertOptions{
+		ConflictColumns: []string{"email"},
+		DoNothing:       true,
+	})
+	require.Nil(t, err)
+	require.Equal(t, created.ID, kept.ID)
+	require.Equal(t, "A2", kept.Name)
+	found, err := repo.FindByID(created.ID)
+	require.Nil(t, err)
+	require.Equal(t, "A2", found.Name)
+
+	_, err = repo.Upsert(&UpsertInput{Email: "a@example.com", Name: "ignored"}, sqlorm.UpsertOptions{
+		DoNothing: true,
+	})
+	require.ErrorIs(t, err, sqlorm.ErrConflictSkipped)
+
+	// Batch
+	result, err := repo.BatchUpsert([]*UpsertInput{
+		{Email: "a@example.com", Name: "A3"},
+		{Email: "b@example.com", Name: "B"},
+		{Email: "c@example.com", Name: "C"},
+	}, 2, sqlorm.UpsertOptions{
+		ConflictColumns: []string{"email"},
+		UpdateColumns:   []string{"name"},
+	})
+	require.Nil(t, err)
+	require.Len(t, result, 3)
+	require.Equal(t, created.ID, result[0].ID)
+	require.Equal(t, "A3", result[0].Name)
+	require.NotZero(t, result[1].ID)
+	require.Equal(t, "pending", result[2].Status)
+
+	count, err = repo.Count(nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), count)
+
+	// Skipped rows in a batch do not shift the returned values
+	result, err = repo.BatchUpsert([]*UpsertInput{
+		{Email: "d@example.com", Name: "D"},
+		{Email: "b@example.com", Name: "ignored"},
+		{Email: "e@example.com", Name: "E"},
+	}, 3, sqlorm.UpsertOptions{
+		ConflictColumns: []string{"email"},
+		DoNothing:       true,
+	})
+	require.Nil(t, err)
+	require.Len(t, result, 3)
+	require.Equal(t, "D", result[0].Name)
+	require.Equal(t, "d@example.com", result[0].Email)
+	require.Equal(t, "B", result[1].Name)
+	require.NotZero(t, result[1].ID)
+	require.Equal(t, "E", result[2].Name)
+	require.Equal(t, "e@example.com", result[2].Email)
+	require.NotEqual(t, result[0].ID, result[2].ID)
+
+	// Invalid columns
+	_, err = repo.Upsert(&UpsertInput{Email: "d@example.com"}, sqlorm.UpsertOptions{
+		ConflictColumns: []string{"email; DROP TABLE upsert_users"},
+	})
+	require.NotNil(t, err)
+}
+
+func Test_OptimisticLock(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type VersionedTodo struct {
+		gorm.Model
+		sqlorm.Versioned
+		Name string `gorm:"type:varchar(255);not null"`
+		Hits int    `gorm:"not null;default:0"`
+	}
+	err := db.AutoMigrate(&VersionedTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[VersionedTodo]{DB: db}
+
+	created, err := repo.Create(&VersionedTodo{Name: "v1"})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), created.Version)
+
+	first, err := repo.FindByID(created.ID)
+	require.Nil(t, err)
+	second, err := repo.FindByID(created.ID)
+	require.Nil(t, err)
+
+	first.Name = "first"
+	updated, err := repo.UpdateByID(first.ID, first)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), updated.Version)
+
+	second.Name = "second"
+	_, err = repo.UpdateByID(second.ID, second)
+	require.ErrorIs(t, err, sqlorm.ErrStaleObject)
+
+	found, err := repo.FindByID(created.ID)
+	require.Nil(t, err)
+	require.Equal(t, "first", found.Name)
+	require.Equal(t, int64(2), found.Version)
+
+	// Bulk updates bump the version too
+	stale, err := repo.FindByID(created.ID)
+	require.Nil(t, err)
+	todos, _, err := repo.UpdateMany(map[string]any{"id": created.ID}, map[string]any{"Name": "bulk"})
+	require.Nil(t, err)
+	require.Len(t, todos, 1)
+	require.Equal(t, int64(3), todos[0].Version)
+
+	err = repo.Query().Where(map[string]any{"id": created.ID}).Update(map[string]any{"Name": "chain"})
+	require.Nil(t, err)
+	err = repo.Increment(created.ID, "Hits", 1)
+	require.Nil(t, err)
+	found, err = repo.FindByID(created.ID)
+	require.Nil(t, err)
+	require.Equal(t, int64(5), found.Version)
+	require.Equal(t, 1, found.Hits)
+
+	stale.Name = "stale"
+	_, err = repo.UpdateByID(stale.ID, stale)
+	require.ErrorIs(t, err, sqlorm.ErrStaleObject)
+
+	// DTOs and maps carry the version of the embedded Versioned
+	type RenameTodo struct {
+		Name    string
+		Version int64
+	}
+	updated, err = repo.UpdateByID(created.ID, &RenameTodo{Name: "dto", Version: 5})
+	require.Nil(t, err)
+	require.Equal(t, "dto", updated.Name)
+	require.Equal(t, int64(6), updated.Version)
+
+	updated, err = repo.UpdateByID(created.ID, map[string]any{"Name": "map", "Version": 6})
+	require.Nil(t, err)
+	require.Equal(t, "map", updated.Name)
+	require.Equal(t, int64(7), updated.Version)
+
+	_, err = repo.UpdateByID(created.ID, map[string]any{"Name": "map", "Version": 6})
+	require.ErrorIs(t, err, sqlorm.ErrStaleObject)
+
+	_, err = repo.UpdateByID(created.ID, map[string]any{"Name": "unversioned"})
+	require.ErrorIs(t, err, sqlorm.ErrMissingVersion)
+	_, err = repo.UpdateByID(created.ID, &RenameTodo{Name: "unversioned"})
+	require.ErrorIs(t, err, sqlorm.ErrMissingVersion)
+
+	// Tag based version field
+	type TaggedTodo struct {
+		gorm.Model
+		Name     string `gorm:"type:varchar(255);not null"`
+		Revision int    `gorm:"not null" sqlorm:"version"`
+	}
+	err = db.AutoMigrate(&TaggedTodo{})
+	require.Nil(t, err)
+
+	taggedRepo := sqlorm.Repository[TaggedTodo]{DB: db}
+	tagged, err := taggedRepo.Create(&TaggedTodo{Name: "tagged"})
+	require.Nil(t, err)
+	require.Equal(t, 1, tagged.Revision)
+
+	_, err = taggedRepo.UpdateOne(map[string]any{"id": tagged.ID}, map[string]any{"Name": "stale", "Revision": 5})
+	require.ErrorIs(t, err, sqlorm.ErrStaleObject)
+
+	result, err := taggedRepo.UpdateOne(map[string]any{"id": tagged.ID}, map[string]any{"Name": "fresh", "Revision": 1})
+	require.Nil(t, err)
+	require.Equal(t, 2, result.Revision)
+}
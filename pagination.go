@@ -0,0 +1,64 @@
+package sqlorm
+
+import "github.com/tinh-tinh/tinhtinh/v2/common"
+
+type Page[M any] struct {
+	Items      []*M  `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PerPage    int   `json:"perPage"`
+	TotalPages int   `json:"totalPages"`
+	HasNext    bool  `json:"hasNext"`
+	HasPrev    bool  `json:"hasPrev"`
+}
+
+const DefaultPerPage = 10
+
+// Paginate returns the rows of one page together with the page metadata.
+// Pages are 1-based; a page below 1 is treated as the first page and a page
+// past the end as the last one.
+func (repo *Repository[M]) Paginate(where Query, page int, perPage int, options ...FindOptions) (*Page[M], error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+
+	var opt FindOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+	opt.Limit = perPage
+	opt.Offset = (page - 1) * perPage
+
+	items, total, err := repo.FindAllAndCount(where, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(perPage) - 1) / int64(perPage))
+	if page > totalPages {
+		page = max(totalPages, 1)
+		if totalPages > 0 {
+			opt.Offset = (page - 1) * perPage
+			items, err = repo.FindAll(where, opt)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if items == nil {
+		items = []*M{}
+	}
+
+	return &Page[M]{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package sqlorm_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+func Test_Paginate(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type PageTodo struct {
+		gorm.Model
+		Name   string `gorm:"type:varchar(255);not null"`
+		Status string `gorm:"type:varchar(50)"`
+	}
+	err := db.AutoMigrate(&PageTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[PageTodo]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	var todos []*PageTodo
+	for i := 0; i < 12; i++ {
+		status := "active"
+		if i%4 == 0 {
+			status = "inactive"
+		}
+		todos = append(todos, &PageTodo{Name: fmt.Sprintf("todo %02d", i), Status: status})
+	}
+	_, err = repo.BatchCreate(todos, 20)
+	require.Nil(t, err)
+
+	page, err := repo.Paginate(nil, 2, 5, sqlorm.FindOptions{Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Equal(t, int64(12), page.Total)
+	require.Equal(t, 2, page.Page)
+	require.Equal(t, 5, page.PerPage)
+	require.Equal(t, 3, page.TotalPages)
+	require.True(t, page.HasNext)
+	require.True(t, page.HasPrev)
+	require.Len(t, page.Items, 5)
+	require.Equal(t, "todo 05", page.Items[0].Name)
+
+	// Out-of-range pages are clamped
+	page, err = repo.Paginate(nil, 10, 5, sqlorm.FindOptions{Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Equal(t, 3, page.Page)
+	require.False(t, page.HasNext)
+	require.Len(t, page.Items, 2)
+
+	page, err = repo.Paginate(nil, -1, 0)
+	require.Nil(t, err)
+	require.Equal(t, 1, page.Page)
+	require.Equal(t, sqlorm.DefaultPerPage, page.PerPage)
+	require.False(t, page.HasPrev)
+
+	// Count follows the same options as the find
+	err = repo.DeleteOne(map[string]any{"name": "todo 00"})
+	require.Nil(t, err)
+
+	page, err = repo.Paginate(map[string]any{"status": "inactive"}, 1, 5)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), page.Total)
+
+	page, err = repo.Paginate(map[string]any{"status": "inactive"}, 1, 5, sqlorm.FindOptions{WithDeleted: true})
+	require.Nil(t, err)
+	require.Equal(t, int64(3), page.Total)
+	require.Len(t, page.Items, 3)
+
+	// Empty result
+	page, err = repo.Paginate(map[string]any{"status": "unknown"}, 3, 5)
+	require.Nil(t, err)
+	require.Equal(t, 1, page.Page)
+	require.Equal(t, 0, page.TotalPages)
+	require.NotNil(t, page.Items)
+
+	data, err := json.Marshal(page)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"items":[],"total":0,"page":1,"perPage":5,"totalPages":0,"hasNext":false,"hasPrev":false}`, string(data))
+}
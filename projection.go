@@ -0,0 +1,154 @@
+package sqlorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type joinedRelation struct {
+	path   string
+	prefix string
+	alias  string
+	schema *schema.Schema
+}
+
+// FindAllAs runs FindAll for M but selects only the columns D needs and scans
+// the rows into D. A field of D is read from the column of M with the same
+// name, from a column of a relation in Related when the field name starts
+// with the relation name (AuthorName for "Author"), or from the column given
+// by its sqlorm tag such as `sqlorm:"Author.name"`. Fields tagged `sqlorm:"-"`
+// are left empty. Related relations are joined, Separate is not supported.
+func FindAllAs[M any, D any](repo *Repository[M], where Query, options ...FindOptions) ([]D, error) {
+	var opt FindOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+	if opt.Separate && len(opt.Related) > 0 {
+		return nil, fmt.Errorf("sqlorm: FindAllAs only supports joined relations")
+	}
+
+	sch, err := repo.schema()
+	if err != nil {
+		return nil, err
+	}
+	var dto D
+	stmt := &gorm.Statement{DB: repo.DB}
+	if err := stmt.Parse(&dto); err != nil {
+		return nil, err
+	}
+
+	relations := make([]joinedRelation, 0, len(opt.Related))
+	for _, path := range opt.Related {
+		relation, err := lookUpRelation(sch, path)
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, relation)
+	}
+
+	var selects []string
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		column, err := projectedColumn(sch, relations, field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stmt.Schema.Name, err)
+		}
+		if column.Name == "" {
+			continue
+		}
+		selects = append(selects, repo.DB.Statement.Quote(column))
+	}
+	if len(selects) == 0 {
+		return nil, fmt.Errorf("sqlorm: %s has no column to select", stmt.Schema.Name)
+	}
+
+	opt.Related = nil
+	opt.Select = nil
+	tx := repo.findQuery(where, opt)
+	for _, relation := range relations {
+		tx = tx.Joins(relation.path, repo.DB.Session(&gorm.Session{NewDB: true}).Omit("*"))
+	}
+
+	var result []D
+	var model M
+	query := tx.Model(&model).Select(strings.Join(selects, ", ")).Scan(&result)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return result, nil
+}
+
+func lookUpRelation(sch *schema.Schema, path string) (joinedRelation, error) {
+	current := sch
+	names := strings.Split(path, ".")
+	for _, name := range names {
+		relation, ok := current.Relationships.Relations[name]
+		if !ok {
+			return joinedRelation{}, fmt.Errorf("sqlorm: %s has no relation %q", current.Name, name)
+		}
+		current = relation.FieldSchema
+	}
+	return joinedRelation{
+		path:   path,
+		prefix: strings.Join(names, ""),
+		alias:  strings.Join(names, "__"),
+		schema: current,
+	}, nil
+}
+
+// projectedColumn returns the column read into field, aliased to the name
+// gorm scans into it. An empty column means the field is skipped.
+func projectedColumn(sch *schema.Schema, relations []joinedRelation, field *schema.Field) (clause.Column, error) {
+	tag := field.Tag.Get("sqlorm")
+	if tag == "-" {
+		return clause.Column{}, nil
+	}
+
+	if tag != "" {
+		for _, part := range strings.Split(tag, ".") {
+			if !isValidAlias(part) {
+				return clause.Column{}, fmt.Errorf("%w %q for %s", ErrInvalidColumn, tag, field.Name)
+			}
+		}
+		idx := strings.LastIndex(tag, ".")
+		if idx < 0 || tag[:idx] == sch.Table {
+			if target := sch.LookUpField(tag[idx+1:]); target != nil && target.DBName != "" {
+				return clause.Column{Table: sch.Table, Name: target.DBName, Alias: field.DBName}, nil
+			}
+			return clause.Column{}, fmt.Errorf("%w %q for %s", ErrInvalidColumn, tag, field.Name)
+		}
+		for _, relation := range relations {
+			if relation.path != tag[:idx] {
+				continue
+			}
+			if target := relation.schema.LookUpField(tag[idx+1:]); target != nil && target.DBName != "" {
+				return clause.Column{Table: relation.alias, Name: target.DBName, Alias: field.DBName}, nil
+			}
+		}
+		return clause.Column{}, fmt.Errorf("%w %q for %s, is the relation in Related?", ErrInvalidColumn, tag, field.Name)
+	}
+
+	if target := sch.LookUpField(field.Name); target != nil && target.DBName != "" {
+		return clause.Column{Table: sch.Table, Name: target.DBName, Alias: field.DBName}, nil
+	}
+	if target := sch.LookUpField(field.DBName); target != nil && target.DBName != "" {
+		return clause.Column{Table: sch.Table, Name: target.DBName, Alias: field.DBName}, nil
+	}
+	for _, relation := range relations {
+		name, ok := strings.CutPrefix(field.Name, relation.prefix)
+		if !ok || name == "" {
+			continue
+		}
+		if target := relation.schema.LookUpField(name); target != nil && target.DBName != "" {
+			return clause.Column{Table: relation.alias, Name: target.DBName, Alias: field.DBName}, nil
+		}
+	}
+	return clause.Column{}, fmt.Errorf("%w: no column for field %s", ErrInvalidColumn, field.Name)
+}
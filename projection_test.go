@@ -0,0 +1,93 @@
+package sqlorm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+type ProjPublisher struct {
+	gorm.Model
+	Name string `gorm:"type:varchar(255)"`
+}
+
+type ProjBook struct {
+	gorm.Model
+	Title           string `gorm:"type:varchar(255)"`
+	Summary         string `gorm:"type:text"`
+	Price           int
+	ProjPublisherID uint
+	ProjPublisher   ProjPublisher
+}
+
+type BookItem struct {
+	ID    uint
+	Title string
+}
+
+type BookWithPublisher struct {
+	Title             string
+	ProjPublisherName string
+	Publisher         string `sqlorm:"ProjPublisher.name"`
+	Cost              int    `sqlorm:"price"`
+	Note              string `sqlorm:"-"`
+}
+
+func Test_FindAllAs(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&ProjPublisher{}, &ProjBook{})
+	require.Nil(t, err)
+
+	publisherRepo := sqlorm.Repository[ProjPublisher]{DB: db}
+	repo := sqlorm.Repository[ProjBook]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+	err = publisherRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	publisher, err := publisherRepo.Create(&ProjPublisher{Name: "Gopher Press"})
+	require.Nil(t, err)
+	_, err = repo.BatchCreate([]*ProjBook{
+		{Title: "Go in Action", Summary: "long text", Price: 30, ProjPublisherID: publisher.ID},
+		{Title: "Learning SQL", Summary: "long text", Price: 25, ProjPublisherID: publisher.ID},
+	}, 2)
+	require.Nil(t, err)
+
+	items, err := sqlorm.FindAllAs[ProjBook, BookItem](&repo, nil, sqlorm.FindOptions{
+		Order: []string{"title"},
+	})
+	require.Nil(t, err)
+	require.Len(t, items, 2)
+	require.NotZero(t, items[0].ID)
+	require.Equal(t, "Go in Action", items[0].Title)
+
+	books, err := sqlorm.FindAllAs[ProjBook, BookWithPublisher](&repo, func(qb *sqlorm.QueryBuilder) {
+		qb.MoreThan("proj_books.price", 26)
+	}, sqlorm.FindOptions{Related: []string{"ProjPublisher"}})
+	require.Nil(t, err)
+	require.Equal(t, []BookWithPublisher{{
+		Title:             "Go in Action",
+		ProjPublisherName: "Gopher Press",
+		Publisher:         "Gopher Press",
+		Cost:              30,
+	}}, books)
+
+	// The relation must be joined
+	_, err = sqlorm.FindAllAs[ProjBook, BookWithPublisher](&repo, nil)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+
+	_, err = sqlorm.FindAllAs[ProjBook, BookWithPublisher](&repo, nil, sqlorm.FindOptions{
+		Related:  []string{"ProjPublisher"},
+		Separate: true,
+	})
+	require.NotNil(t, err)
+
+	type Unknown struct {
+		Missing string
+	}
+	_, err = sqlorm.FindAllAs[ProjBook, Unknown](&repo, nil)
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+}
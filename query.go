@@ -1,6 +1,7 @@
 package sqlorm
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/tinh-tinh/tinhtinh/v2/common"
@@ -11,8 +12,11 @@ type FindOneOptions struct {
 	Select      []string
 	Order       []string
 	WithDeleted bool
+	OnlyDeleted bool
 	Related     []string
 	Separate    bool
+	Lock        *Lock
+	Count       CountOptions
 }
 
 type FindOptions struct {
@@ -20,14 +24,30 @@ type FindOptions struct {
 	Select      []string
 	Order       []string
 	WithDeleted bool
+	OnlyDeleted bool
 	Limit       int
 	Offset      int
 	Related     []string
 	Separate    bool
+	Lock        *Lock
+	// WindowCount makes FindAllAndCount read the rows and the total in one
+	// query with COUNT(*) OVER().
+	WindowCount bool
+	Count       CountOptions
 }
 
 func (repo *Repository[M]) FindAll(where Query, options ...FindOptions) ([]*M, error) {
 	var model []*M
+	tx := repo.findQuery(where, options...)
+
+	result := tx.Find(&model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return model, nil
+}
+
+func (repo *Repository[M]) findQuery(where Query, options ...FindOptions) *gorm.DB {
 	tx := repo.DB
 
 	var opt FindOptions
@@ -63,23 +83,12 @@ func (repo *Repository[M]) FindAll(where Query, options ...FindOptions) ([]*M, e
 	if opt.WithDeleted {
 		tx = tx.Unscoped()
 	}
-
-	if IsQueryBuilder(where) {
-		queryFnc, ok := where.(func(qb *QueryBuilder))
-		if ok {
-			qb := &QueryBuilder{qb: tx}
-			queryFnc(qb)
-			tx = qb.qb
-		}
-	} else {
-		tx = tx.Where(where)
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
 	}
+	tx = applyLock(tx, opt.Lock)
 
-	result := tx.Find(&model)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return model, nil
+	return repo.applyQuery(tx, where)
 }
 
 func (repo *Repository[M]) FindOne(where Query, options ...FindOneOptions) (*M, error) {
@@ -110,17 +119,12 @@ func (repo *Repository[M]) FindOne(where Query, options ...FindOneOptions) (*M,
 	if opt.WithDeleted {
 		tx = tx.Unscoped()
 	}
-
-	if IsQueryBuilder(where) {
-		queryFnc, ok := where.(func(qb *QueryBuilder))
-		if ok {
-			qb := &QueryBuilder{qb: tx}
-			queryFnc(qb)
-			tx = qb.qb
-		}
-	} else {
-		tx = tx.Where(where)
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
 	}
+	tx = applyLock(tx, opt.Lock)
+
+	tx = repo.applyQuery(tx, where)
 
 	result := tx.First(&model)
 	if result.Error != nil {
@@ -136,6 +140,8 @@ func (repo *Repository[M]) FindByID(id any, options ...FindOneOptions) (*M, erro
 	return repo.FindOne(map[string]interface{}{"id": id}, options...)
 }
 
+// Count returns the number of rows matching where. Setting Lock fails with
+// ErrLockAggregate, since a count cannot lock the rows it counts.
 func (repo *Repository[M]) Count(where interface{}, options ...FindOneOptions) (int64, error) {
 	var count int64
 	var model M
@@ -144,21 +150,21 @@ func (repo *Repository[M]) Count(where interface{}, options ...FindOneOptions) (
 	if len(options) > 0 {
 		opt = common.MergeStruct(options...)
 	}
+	if opt.Lock != nil {
+		return 0, ErrLockAggregate
+	}
 
 	tx := repo.DB.Model(&model)
 	if opt.WithDeleted {
 		tx = tx.Unscoped()
 	}
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
+	}
 
-	if IsQueryBuilder(where) {
-		queryFnc, ok := where.(func(qb *QueryBuilder))
-		if ok {
-			qb := &QueryBuilder{qb: tx}
-			queryFnc(qb)
-			tx = qb.qb
-		}
-	} else {
-		tx = tx.Where(where)
+	tx = repo.applyQuery(tx, where)
+	if opt.Count.Approximate {
+		return repo.approximateCount(tx, opt.Count)
 	}
 
 	result := tx.Count(&count)
@@ -187,17 +193,12 @@ func (repo *Repository[M]) Exist(where Query, options ...FindOneOptions) (bool,
 	if opt.WithDeleted {
 		tx = tx.Unscoped()
 	}
-
-	if IsQueryBuilder(where) {
-		queryFnc, ok := where.(func(qb *QueryBuilder))
-		if ok {
-			qb := &QueryBuilder{qb: tx}
-			queryFnc(qb)
-			tx = qb.qb
-		}
-	} else {
-		tx = tx.Where(where)
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
 	}
+	tx = applyLock(tx, opt.Lock)
+
+	tx = repo.applyQuery(tx, where)
 
 	result := tx.First(&model)
 	if result.Error != nil {
@@ -210,6 +211,28 @@ func (repo *Repository[M]) Exist(where Query, options ...FindOneOptions) (bool,
 }
 
 func (repo *Repository[M]) FindAllAndCount(where Query, options ...FindOptions) ([]*M, int64, error) {
+	var opt FindOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+	if opt.WindowCount && !opt.Count.Approximate {
+		return repo.findAllAndCountOver(where, opt)
+	}
+
+	// A transaction holds a single connection, which cannot run two queries
+	// at once.
+	if _, ok := repo.DB.Statement.ConnPool.(gorm.TxCommitter); ok {
+		records, err := repo.FindAll(where, options...)
+		if err != nil {
+			return nil, 0, err
+		}
+		count, err := repo.countAll(where, options...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return records, count, nil
+	}
+
 	var wg sync.WaitGroup
 	var findAllRes []*M
 	var countRes int64
@@ -224,7 +247,7 @@ func (repo *Repository[M]) FindAllAndCount(where Query, options ...FindOptions)
 
 	go func() {
 		defer wg.Done()
-		countRes, countErr = repo.Count(where)
+		countRes, countErr = repo.countAll(where, options...)
 	}()
 
 	wg.Wait()
@@ -238,3 +261,69 @@ func (repo *Repository[M]) FindAllAndCount(where Query, options ...FindOptions)
 
 	return findAllRes, countRes, nil
 }
+
+// countAll counts the rows FindAll would return for the same options,
+// ignoring paging and ordering.
+func (repo *Repository[M]) countAll(where Query, options ...FindOptions) (int64, error) {
+	var count int64
+	var model M
+
+	var opt FindOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+
+	tx := repo.DB.Model(&model)
+	if len(opt.Related) > 0 && !opt.Separate {
+		for _, key := range opt.Related {
+			tx = tx.Joins(key)
+		}
+	}
+	if len(opt.Distinct) > 0 {
+		tx = tx.Distinct(opt.Distinct...)
+	}
+	if opt.WithDeleted {
+		tx = tx.Unscoped()
+	}
+	if opt.OnlyDeleted {
+		tx = repo.applyOnlyDeleted(tx)
+	}
+
+	tx = repo.applyQuery(tx, where)
+	if opt.Count.Approximate {
+		return repo.approximateCount(tx, opt.Count)
+	}
+
+	result := tx.Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (repo *Repository[M]) applyQuery(tx *gorm.DB, where Query) *gorm.DB {
+	if IsQueryBuilder(where) {
+		queryFnc, ok := where.(func(qb *QueryBuilder))
+		if ok {
+			qb := &QueryBuilder{qb: tx, strict: repo.Strict}
+			queryFnc(qb)
+			tx = qb.qb
+			if qb.strict && qb.raw {
+				qb.errs = append(qb.errs, fmt.Errorf("%w: raw sql in strict mode", ErrUnsafeSQL))
+			}
+			if len(qb.errs) > 0 {
+				tx = withError(tx, qb.Err())
+			}
+		}
+		return tx
+	}
+	return tx.Where(where)
+}
+
+// withError records err on a new session so the shared connection stays
+// usable; gorm then skips executing the statement and returns err.
+func withError(tx *gorm.DB, err error) *gorm.DB {
+	tx = tx.Session(&gorm.Session{})
+	_ = tx.AddError(err)
+	return tx
+}
@@ -1,6 +1,7 @@
 package sqlorm_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -516,3 +517,266 @@ func Test_FindAndCount(t *testing.T) {
 	require.Equal(t, int64(4), total)
 	require.Equal(t, "Test Todo 1", result[0].Name)
 }
+
+func Test_WithContext(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type ContextTodo struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255);not null"`
+	}
+	err := db.AutoMigrate(&ContextTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[ContextTodo]{DB: db}
+
+	_, err = repo.WithContext(context.Background()).Create(&ContextTodo{Name: "context"})
+	require.Nil(t, err)
+
+	result, total, err := repo.WithContext(context.Background()).FindAllAndCount(map[string]any{"name": "context"})
+	require.Nil(t, err)
+	require.Equal(t, int64(len(result)), total)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctxRepo := repo.WithContext(ctx)
+	_, err = ctxRepo.FindAll(nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = ctxRepo.Count(nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, _, err = ctxRepo.FindAllAndCount(nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = ctxRepo.Create(&ContextTodo{Name: "canceled"})
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The original repository is not bound to the canceled context
+	_, err = repo.FindAll(nil)
+	require.Nil(t, err)
+}
+
+func Test_Lock(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type Inventory struct {
+		gorm.Model
+		Sku      string `gorm:"type:varchar(255);not null"`
+		Reserved bool
+	}
+	err := db.AutoMigrate(&Inventory{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[Inventory]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+	_, err = repo.BatchCreate([]*Inventory{{Sku: "a"}, {Sku: "b"}}, 5)
+	require.Nil(t, err)
+
+	// Outside a transaction
+	_, err = repo.FindOne(nil, sqlorm.FindOneOptions{Lock: &sqlorm.Lock{Strength: sqlorm.LockForUpdate}})
+	require.ErrorIs(t, err, sqlorm.ErrLockOutsideTransaction)
+
+	_, err = repo.FindAll(nil, sqlorm.FindOptions{Lock: &sqlorm.Lock{}})
+	require.ErrorIs(t, err, sqlorm.ErrLockOutsideTransaction)
+
+	_, err = repo.Count(nil, sqlorm.FindOneOptions{Lock: &sqlorm.Lock{}})
+	require.ErrorIs(t, err, sqlorm.ErrLockAggregate)
+
+	// The repository is still usable afterwards
+	_, err = repo.FindAll(nil)
+	require.Nil(t, err)
+
+	// Reserve rows concurrently with SKIP LOCKED
+	err = repo.Transaction(func(tx *sqlorm.Repository[Inventory]) error {
+		first, err := tx.FindOne(map[string]any{"reserved": false}, sqlorm.FindOneOptions{
+			Order: []string{"id"},
+			Lock:  &sqlorm.Lock{Strength: sqlorm.LockForUpdate, Options: sqlorm.LockSkipLocked},
+		})
+		if err != nil {
+			return err
+		}
+		require.Equal(t, "a", first.Sku)
+
+		_, err = tx.Count(nil, sqlorm.FindOneOptions{Lock: &sqlorm.Lock{}})
+		require.ErrorIs(t, err, sqlorm.ErrLockAggregate)
+
+		err = repo.Transaction(func(other *sqlorm.Repository[Inventory]) error {
+			rows, err := other.FindAll(map[string]any{"reserved": false}, sqlorm.FindOptions{
+				Order: []string{"id"},
+				Lock:  &sqlorm.Lock{Strength: sqlorm.LockForUpdate, Options: sqlorm.LockSkipLocked},
+			})
+			if err != nil {
+				return err
+			}
+			require.Len(t, rows, 1)
+			require.Equal(t, "b", rows[0].Sku)
+
+			_, err = other.FindOne(map[string]any{"sku": "a"}, sqlorm.FindOneOptions{
+				Lock: &sqlorm.Lock{Strength: sqlorm.LockForUpdate, Options: sqlorm.LockNoWait},
+			})
+			return err
+		})
+		// The row locked by the outer transaction cannot be taken with NOWAIT
+		require.NotNil(t, err)
+		return nil
+	})
+	require.Nil(t, err)
+}
+
+func Test_FindAndCountWindow(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type WindowTeam struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255)"`
+	}
+	type WindowPlayer struct {
+		gorm.Model
+		Name         string `gorm:"type:varchar(255)"`
+		Position     string `gorm:"type:varchar(50)"`
+		WindowTeamID uint
+		WindowTeam   WindowTeam
+	}
+	err := db.AutoMigrate(&WindowTeam{}, &WindowPlayer{})
+	require.Nil(t, err)
+
+	teamRepo := sqlorm.Repository[WindowTeam]{DB: db}
+	repo := sqlorm.Repository[WindowPlayer]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+	err = teamRepo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	team, err := teamRepo.Create(&WindowTeam{Name: "Blue"})
+	require.Nil(t, err)
+	_, err = repo.BatchCreate([]*WindowPlayer{
+		{Name: "a", Position: "forward", WindowTeamID: team.ID},
+		{Name: "b", Position: "forward", WindowTeamID: team.ID},
+		{Name: "c", Position: "keeper", WindowTeamID: team.ID},
+		{Name: "d", Position: "defender", WindowTeamID: team.ID},
+		{Name: "e", Position: "defender", WindowTeamID: team.ID},
+	}, 5)
+	require.Nil(t, err)
+	err = repo.DeleteOne(map[string]any{"name": "e"})
+	require.Nil(t, err)
+
+	// Same result as the two-query mode
+	options := sqlorm.FindOptions{Order: []string{"name"}, Limit: 2, Offset: 1, Related: []string{"WindowTeam"}}
+	players, total, err := repo.FindAllAndCount(nil, options)
+	require.Nil(t, err)
+	windowOptions := options
+	windowOptions.WindowCount = true
+	windowPlayers, windowTotal, err := repo.FindAllAndCount(nil, windowOptions)
+	require.Nil(t, err)
+
+	require.Equal(t, int64(4), total)
+	require.Equal(t, total, windowTotal)
+	require.Len(t, windowPlayers, 2)
+	for i := range players {
+		require.Equal(t, players[i].Name, windowPlayers[i].Name)
+		require.Equal(t, "Blue", windowPlayers[i].WindowTeam.Name)
+	}
+
+	// Conditions and deleted rows
+	players, total, err = repo.FindAllAndCount(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("position", "defender")
+	}, sqlorm.FindOptions{WithDeleted: true, WindowCount: true})
+	require.Nil(t, err)
+	require.Len(t, players, 2)
+	require.Equal(t, int64(2), total)
+
+	// Distinct uses two queries
+	players, total, err = repo.FindAllAndCount(nil, sqlorm.FindOptions{
+		Distinct:    []interface{}{"position"},
+		WindowCount: true,
+	})
+	require.Nil(t, err)
+	require.Len(t, players, 3)
+	require.Equal(t, int64(3), total)
+
+	// A page past the end still reports the total
+	players, total, err = repo.FindAllAndCount(nil, sqlorm.FindOptions{Limit: 2, Offset: 10, WindowCount: true})
+	require.Nil(t, err)
+	require.Empty(t, players)
+	require.Equal(t, int64(4), total)
+
+	page, err := repo.Paginate(nil, 3, 3, sqlorm.FindOptions{Order: []string{"name"}, WindowCount: true})
+	require.Nil(t, err)
+	require.Equal(t, 2, page.Page)
+	require.Equal(t, int64(4), page.Total)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, "d", page.Items[0].Name)
+}
+
+func Test_ApproximateCount(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type Measurement struct {
+		gorm.Model
+		Sensor string `gorm:"type:varchar(50)"`
+		Value  float64
+	}
+	err := db.AutoMigrate(&Measurement{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[Measurement]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	measurements := make([]*Measurement, 0, 200)
+	for i := 0; i < 200; i++ {
+		sensor := "temperature"
+		if i%4 == 0 {
+			sensor = "humidity"
+		}
+		measurements = append(measurements, &Measurement{Sensor: sensor, Value: float64(i)})
+	}
+	_, err = repo.BatchCreate(measurements, 50)
+	require.Nil(t, err)
+
+	// Under the threshold the exact count is returned
+	count, err := repo.Count(nil, sqlorm.FindOneOptions{Count: sqlorm.CountOptions{Approximate: true}})
+	require.Nil(t, err)
+	require.Equal(t, int64(200), count)
+
+	_, total, err := repo.FindAllAndCount(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("sensor", "humidity")
+	}, sqlorm.FindOptions{Limit: 10, WindowCount: true, Count: sqlorm.CountOptions{Approximate: true}})
+	require.Nil(t, err)
+	require.Equal(t, int64(50), total)
+
+	// Planner estimates once the statistics are known
+	err = db.Exec("ANALYZE measurements").Error
+	require.Nil(t, err)
+
+	count, err = repo.Count(nil, sqlorm.FindOneOptions{
+		WithDeleted: true,
+		Count:       sqlorm.CountOptions{Approximate: true, Threshold: 1},
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(200), count)
+
+	count, err = repo.Count(map[string]any{"sensor": "humidity"}, sqlorm.FindOneOptions{
+		Count: sqlorm.CountOptions{Approximate: true, Threshold: 1},
+	})
+	require.Nil(t, err)
+	require.Greater(t, count, int64(0))
+	require.Less(t, count, int64(200))
+
+	// Full-text conditions keep their bound values
+	search := func(qb *sqlorm.QueryBuilder) {
+		qb.Search([]string{"sensor"}, "humidity")
+	}
+	count, err = repo.Count(search, sqlorm.FindOneOptions{Count: sqlorm.CountOptions{Approximate: true}})
+	require.Nil(t, err)
+	require.Equal(t, int64(50), count)
+
+	count, err = repo.Count(search, sqlorm.FindOneOptions{
+		Count: sqlorm.CountOptions{Approximate: true, Threshold: 1},
+	})
+	require.Nil(t, err)
+	require.Greater(t, count, int64(0))
+}
@@ -0,0 +1,77 @@
+package sqlorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnsafeSQL = errors.New("sqlorm: unsafe raw sql")
+
+// RawQuery runs sql and scans every row into T, which can be a struct or a
+// scalar for single column results. Values are bound through ? placeholders
+// or named parameters like @status given as sql.Named, a map or a struct.
+// Statements containing string literals, comments or several statements are
+// rejected with ErrUnsafeSQL, as they usually come from concatenated input.
+func RawQuery[T any](db *gorm.DB, sql string, args ...interface{}) ([]T, error) {
+	if err := checkRawSQL(sql); err != nil {
+		return nil, err
+	}
+	var result []T
+	tx := db.Raw(sql, args...).Scan(&result)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return result, nil
+}
+
+// RawQueryOne is RawQuery for a single row; it returns nil when there is no
+// row.
+func RawQueryOne[T any](db *gorm.DB, sql string, args ...interface{}) (*T, error) {
+	if err := checkRawSQL(sql); err != nil {
+		return nil, err
+	}
+	var result T
+	tx := db.Raw(sql, args...).Scan(&result)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+// checkRawSQL skips quoted identifiers and rejects string literals, dollar
+// quoting, comments and statement separators.
+func checkRawSQL(sql string) error {
+	if strings.TrimSpace(sql) == "" {
+		return fmt.Errorf("%w: empty statement", ErrUnsafeSQL)
+	}
+	sql = strings.TrimRight(strings.TrimSpace(sql), ";")
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; c {
+		case '"':
+			end := strings.IndexByte(sql[i+1:], '"')
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated identifier", ErrUnsafeSQL)
+			}
+			i += end + 1
+		case '\'':
+			return fmt.Errorf("%w: string literal at offset %d, bind it as an argument", ErrUnsafeSQL, i)
+		case '$':
+			if i+1 < len(sql) && (sql[i+1] < '0' || sql[i+1] > '9') {
+				return fmt.Errorf("%w: dollar quoting at offset %d", ErrUnsafeSQL, i)
+			}
+		case ';':
+			return fmt.Errorf("%w: multiple statements", ErrUnsafeSQL)
+		case '-', '/':
+			if i+1 < len(sql) && ((c == '-' && sql[i+1] == '-') || (c == '/' && sql[i+1] == '*')) {
+				return fmt.Errorf("%w: comment at offset %d", ErrUnsafeSQL, i)
+			}
+		}
+	}
+	return nil
+}
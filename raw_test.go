@@ -0,0 +1,94 @@
+package sqlorm_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+type RawOrder struct {
+	gorm.Model
+	Customer string `gorm:"type:varchar(255)"`
+	Status   string `gorm:"type:varchar(50)"`
+	Amount   int
+}
+
+type CustomerTotal struct {
+	Customer string
+	Total    int
+	Orders   int64
+}
+
+func Test_RawQuery(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&RawOrder{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[RawOrder]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*RawOrder{
+		{Customer: "alice", Status: "paid", Amount: 10},
+		{Customer: "alice", Status: "paid", Amount: 15},
+		{Customer: "bob", Status: "paid", Amount: 7},
+		{Customer: "bob", Status: "pending", Amount: 100},
+	}, 4)
+	require.Nil(t, err)
+
+	// Structs with positional arguments
+	totals, err := sqlorm.RawQuery[CustomerTotal](db, `
+		SELECT customer, SUM(amount) AS total, COUNT(*) AS orders
+		FROM raw_orders
+		WHERE status = ? AND deleted_at IS NULL
+		GROUP BY customer
+		ORDER BY customer`, "paid")
+	require.Nil(t, err)
+	require.Equal(t, []CustomerTotal{
+		{Customer: "alice", Total: 25, Orders: 2},
+		{Customer: "bob", Total: 7, Orders: 1},
+	}, totals)
+
+	// Scalars with named arguments
+	customers, err := sqlorm.RawQuery[string](db,
+		"SELECT DISTINCT customer FROM raw_orders WHERE status = @status AND amount >= @min ORDER BY customer",
+		map[string]interface{}{"status": "paid", "min": 10})
+	require.Nil(t, err)
+	require.Equal(t, []string{"alice"}, customers)
+
+	total, err := sqlorm.RawQueryOne[int](db, "SELECT SUM(amount) FROM raw_orders WHERE customer = @customer", sql.Named("customer", "bob"))
+	require.Nil(t, err)
+	require.NotNil(t, total)
+	require.Equal(t, 107, *total)
+
+	one, err := sqlorm.RawQueryOne[CustomerTotal](db, "SELECT customer, amount AS total FROM raw_orders WHERE customer = ? ORDER BY amount DESC LIMIT 1", "bob")
+	require.Nil(t, err)
+	require.NotNil(t, one)
+	require.Equal(t, 100, one.Total)
+
+	none, err := sqlorm.RawQueryOne[CustomerTotal](db, "SELECT customer FROM raw_orders WHERE customer = ?", "nobody")
+	require.Nil(t, err)
+	require.Nil(t, none)
+}
+
+func Test_RawQueryGuard(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"SELECT * FROM raw_orders WHERE customer = 'alice'",
+		"SELECT * FROM raw_orders WHERE customer = '' OR 1=1",
+		"SELECT * FROM raw_orders; DROP TABLE raw_orders",
+		"SELECT * FROM raw_orders -- WHERE deleted_at IS NULL",
+		"SELECT * FROM raw_orders /* comment */",
+		"SELECT $$alice$$",
+		`SELECT * FROM "raw_orders`,
+	} {
+		_, err := sqlorm.RawQuery[RawOrder](nil, query)
+		require.ErrorIs(t, err, sqlorm.ErrUnsafeSQL, query)
+		_, err = sqlorm.RawQueryOne[RawOrder](nil, query)
+		require.ErrorIs(t, err, sqlorm.ErrUnsafeSQL, query)
+	}
+}
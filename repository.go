@@ -1,11 +1,13 @@
 package sqlorm
 
 import (
+	"context"
 	"reflect"
 	"strings"
 
 	"github.com/tinh-tinh/tinhtinh/v2/common"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type RepoCommon interface {
@@ -19,6 +21,9 @@ func NewRepo[M any](model M) *Repository[M] {
 
 type Repository[M any] struct {
 	DB *gorm.DB
+	// Strict also rejects queries whose QueryBuilder adds raw SQL; queries
+	// with invalid conditions are always rejected.
+	Strict bool
 }
 
 func (r *Repository[M]) GetName() string {
@@ -39,6 +44,27 @@ func (r *Repository[M]) SetDB(db *gorm.DB) {
 	r.DB = db
 }
 
+// WithContext returns a clone of the repository whose queries run with ctx,
+// so cancellation and deadlines are propagated to the database.
+func (r *Repository[M]) WithContext(ctx context.Context) *Repository[M] {
+	return r.clone(r.DB.WithContext(ctx))
+}
+
+// newModel has a value receiver so that subqueries accept a repository
+// passed by value as well as by pointer.
+func (r Repository[M]) newModel() interface{} {
+	return new(M)
+}
+
+func (r *Repository[M]) schema() (*schema.Schema, error) {
+	var model M
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(&model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
 func MapOne[M any](data interface{}) *M {
 	var model M
 	if data == nil {
@@ -0,0 +1,64 @@
+package sqlorm
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateReturning updates the rows matched by scope with values, a *M or a
+// column map, and returns them as stored, using RETURNING when the dialect
+// supports it and selecting them again by primary key otherwise.
+func (repo *Repository[M]) updateReturning(scope func(tx *gorm.DB) *gorm.DB, values interface{}) ([]*M, int64, error) {
+	var records []*M
+	if slices.Contains(repo.DB.Callback().Update().Clauses, "RETURNING") {
+		// gorm runs the update hooks on the elements of a slice model, so it
+		// starts with one blank model as a struct model would be; the rows
+		// returned replace it.
+		records = []*M{new(M)}
+		result := repo.DB.Model(&records).Scopes(scope).Clauses(clause.Returning{}).Updates(values)
+		if result.Error != nil {
+			return nil, 0, result.Error
+		}
+		return records, result.RowsAffected, nil
+	}
+
+	sch, err := repo.schema()
+	if err != nil {
+		return nil, 0, err
+	}
+	field := sch.PrioritizedPrimaryField
+	if field == nil {
+		return nil, 0, fmt.Errorf("sqlorm: %s has no primary key to select updated rows", sch.Table)
+	}
+
+	var affected int64
+	err = repo.DB.Transaction(func(tx *gorm.DB) error {
+		var matched []*M
+		if err := tx.Model(new(M)).Scopes(scope).Select(field.DBName).Find(&matched).Error; err != nil {
+			return err
+		}
+		if len(matched) == 0 {
+			return nil
+		}
+		ids := make([]interface{}, len(matched))
+		for i, record := range matched {
+			ids[i], _ = field.ValueOf(tx.Statement.Context, reflect.ValueOf(record).Elem())
+		}
+		byID := clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Values: ids}
+
+		result := tx.Model(new(M)).Scopes(scope).Where(byID).Updates(values)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+		return tx.Where(byID).Find(&records).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, affected, nil
+}
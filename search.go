@@ -0,0 +1,216 @@
+package sqlorm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const DefaultSearchConfig = "english"
+
+const (
+	SearchWebsearch = "websearch"
+	SearchPlain     = "plain"
+	SearchPhrase    = "phrase"
+	SearchRaw       = "raw"
+)
+
+var searchFunctions = map[string]string{
+	SearchWebsearch: "websearch_to_tsquery",
+	SearchPlain:     "plainto_tsquery",
+	SearchPhrase:    "phraseto_tsquery",
+	SearchRaw:       "to_tsquery",
+}
+
+var validSearchConfigRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+type FTSOptions struct {
+	// Config is the text search configuration, DefaultSearchConfig when
+	// empty. It must match the one of the search index.
+	Config string
+	// Mode selects how the query is parsed, SearchWebsearch when empty.
+	Mode string
+	// Rank orders the rows by relevance and selects it as RankAlias.
+	Rank      bool
+	RankAlias string
+	// Headline selects a highlighted snippet of this column as
+	// HeadlineAlias, using HeadlineOptions such as "MaxWords=20".
+	Headline        string
+	HeadlineAlias   string
+	HeadlineOptions string
+	// IndexName overrides the index name of CreateSearchIndex.
+	IndexName string
+}
+
+// Search matches rows whose columns contain query using PostgreSQL full-text
+// search. With Rank or Headline the extra values are selected next to the
+// model columns, so the destination needs fields named after the aliases
+// ("rank" and "headline" by default), e.g. `gorm:"->;-:migration"`.
+func (q *QueryBuilder) Search(columns []string, query string, options ...FTSOptions) *QueryBuilder {
+	var opt FTSOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+	config, err := searchConfig(opt.Config)
+	if err != nil {
+		q.errs = append(q.errs, err)
+		return q
+	}
+	vector, err := tsvectorExpr(config, columns)
+	if err != nil {
+		q.errs = append(q.errs, err)
+		return q
+	}
+	fn, ok := searchFunctions[opt.Mode]
+	if opt.Mode == "" {
+		fn, ok = searchFunctions[SearchWebsearch], true
+	}
+	if !ok {
+		q.errs = append(q.errs, fmt.Errorf("sqlorm: invalid search mode %q", opt.Mode))
+		return q
+	}
+	tsquery := fn + "(" + config + ", ?)"
+
+	var selects []string
+	var vars []interface{}
+	rankAlias := opt.RankAlias
+	if rankAlias == "" {
+		rankAlias = "rank"
+	}
+	if opt.Rank {
+		if !isValidAlias(rankAlias) {
+			q.errs = append(q.errs, fmt.Errorf("%w %q as rank alias", ErrInvalidColumn, rankAlias))
+			return q
+		}
+		selects = append(selects, "ts_rank("+vector+", "+tsquery+") AS "+rankAlias)
+		vars = append(vars, query)
+	}
+	if opt.Headline != "" {
+		alias := opt.HeadlineAlias
+		if alias == "" {
+			alias = "headline"
+		}
+		if !isValidColumn(opt.Headline) {
+			q.errs = append(q.errs, fmt.Errorf("%w %q in headline", ErrInvalidColumn, opt.Headline))
+			return q
+		}
+		if !isValidAlias(alias) {
+			q.errs = append(q.errs, fmt.Errorf("%w %q as headline alias", ErrInvalidColumn, alias))
+			return q
+		}
+		selects = append(selects, "ts_headline("+config+", coalesce("+opt.Headline+", ''), "+tsquery+", ?) AS "+alias)
+		vars = append(vars, query, opt.HeadlineOptions)
+	}
+	if len(selects) > 0 {
+		q.addSelect(strings.Join(selects, ", "), vars...)
+	}
+
+	q.qb = q.qb.Where(vector+" @@ "+tsquery, query)
+	if opt.Rank {
+		q.qb = q.qb.Order(rankAlias + " DESC")
+	}
+	return q
+}
+
+// addSelect adds expressions to the columns already selected, or to all
+// columns of the model when nothing was selected.
+func (q *QueryBuilder) addSelect(sql string, vars ...interface{}) {
+	stmt := q.qb.Statement
+	base := "?.*"
+	baseVars := []interface{}{clause.Table{Name: clause.CurrentTable}}
+	if c, ok := stmt.Clauses["SELECT"]; ok {
+		if expr, ok := c.Expression.(clause.Expr); ok {
+			base, baseVars = expr.SQL, expr.Vars
+		}
+	} else if len(stmt.Selects) > 0 {
+		base, baseVars = strings.Join(stmt.Selects, ", "), nil
+	}
+	q.qb = q.qb.Select(base+", "+sql, append(baseVars, vars...)...)
+}
+
+// CreateSearchIndex creates the GIN index used by Search on the same
+// columns with the same Config.
+func CreateSearchIndex(db *gorm.DB, model interface{}, columns []string, options ...FTSOptions) error {
+	table, name, expr, err := searchIndex(db, model, columns, options...)
+	if err != nil {
+		return err
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS ? ON ? USING GIN ("+expr+")", clause.Column{Name: name}, clause.Table{Name: table}).Error
+}
+
+func DropSearchIndex(db *gorm.DB, model interface{}, columns []string, options ...FTSOptions) error {
+	_, name, _, err := searchIndex(db, model, columns, options...)
+	if err != nil {
+		return err
+	}
+	return db.Exec("DROP INDEX IF EXISTS ?", clause.Column{Name: name}).Error
+}
+
+func searchIndex(db *gorm.DB, model interface{}, columns []string, options ...FTSOptions) (string, string, string, error) {
+	var opt FTSOptions
+	if len(options) > 0 {
+		opt = common.MergeStruct(options...)
+	}
+	config, err := searchConfig(opt.Config)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", "", "", err
+	}
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		if table, name, ok := strings.Cut(column, "."); ok {
+			if table != stmt.Schema.Table {
+				return "", "", "", fmt.Errorf("sqlorm: search column %q does not belong to %s", column, stmt.Schema.Table)
+			}
+			column = name
+		}
+		names[i] = column
+	}
+	expr, err := tsvectorExpr(config, names)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	name := opt.IndexName
+	if name == "" {
+		name = "idx_" + stmt.Schema.Table + "_" + strings.Join(names, "_") + "_fts"
+	}
+	if !isValidAlias(name) {
+		return "", "", "", fmt.Errorf("sqlorm: invalid index name %q", name)
+	}
+	return stmt.Schema.Table, name, expr, nil
+}
+
+func searchConfig(config string) (string, error) {
+	if config == "" {
+		config = DefaultSearchConfig
+	}
+	if !validSearchConfigRegex.MatchString(config) {
+		return "", fmt.Errorf("sqlorm: invalid search config %q", config)
+	}
+	return "'" + config + "'::regconfig", nil
+}
+
+// tsvectorExpr builds the document of a search. Search and
+// CreateSearchIndex share it so that the planner matches the index.
+func tsvectorExpr(config string, columns []string) (string, error) {
+	if len(columns) == 0 {
+		return "", fmt.Errorf("sqlorm: search requires at least one column")
+	}
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		if !isValidColumn(column) {
+			return "", fmt.Errorf("%w %q in search", ErrInvalidColumn, column)
+		}
+		parts[i] = "coalesce(" + column + ", '')"
+	}
+	return "to_tsvector(" + config + ", " + strings.Join(parts, " || ' ' || ") + ")", nil
+}
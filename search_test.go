@@ -0,0 +1,85 @@
+package sqlorm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+type Article struct {
+	gorm.Model
+	Title    string  `gorm:"type:varchar(255)"`
+	Body     string  `gorm:"type:text"`
+	Rank     float64 `gorm:"->;-:migration"`
+	Headline string  `gorm:"->;-:migration"`
+}
+
+func Test_Search(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	err := db.AutoMigrate(&Article{})
+	require.Nil(t, err)
+
+	columns := []string{"title", "body"}
+	err = sqlorm.CreateSearchIndex(db, &Article{}, columns, sqlorm.FTSOptions{Config: "english"})
+	require.Nil(t, err)
+	// Creating it twice is a no-op
+	err = sqlorm.CreateSearchIndex(db, &Article{}, columns, sqlorm.FTSOptions{Config: "english"})
+	require.Nil(t, err)
+	require.True(t, db.Migrator().HasIndex(&Article{}, "idx_articles_title_body_fts"))
+
+	repo := sqlorm.Repository[Article]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*Article{
+		{Title: "Running databases", Body: "Postgres runs fast when indexes are used."},
+		{Title: "Cooking", Body: "A recipe for pasta with tomatoes."},
+		{Title: "Databases and databases", Body: "Indexing databases with GIN makes search fast."},
+	}, 3)
+	require.Nil(t, err)
+
+	// Stemming matches "database" with "databases"
+	articles, err := repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Search(columns, "database", sqlorm.FTSOptions{Config: "english"})
+	})
+	require.Nil(t, err)
+	require.Len(t, articles, 2)
+
+	// Websearch syntax
+	count, err := repo.Count(func(qb *sqlorm.QueryBuilder) {
+		qb.Search(columns, "fast -gin")
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Rank and headline
+	articles, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Search(columns, "databases", sqlorm.FTSOptions{
+			Rank:            true,
+			Headline:        "body",
+			HeadlineOptions: "StartSel=<b>, StopSel=</b>",
+		})
+	})
+	require.Nil(t, err)
+	require.Len(t, articles, 2)
+	require.Equal(t, "Databases and databases", articles[0].Title)
+	require.Greater(t, articles[0].Rank, articles[1].Rank)
+	require.Contains(t, articles[0].Headline, "<b>databases</b>")
+
+	// Invalid input
+	_, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Strict().Search([]string{"title; DROP TABLE articles"}, "x")
+	})
+	require.ErrorIs(t, err, sqlorm.ErrInvalidColumn)
+	_, err = repo.FindAll(func(qb *sqlorm.QueryBuilder) {
+		qb.Strict().Search(columns, "x", sqlorm.FTSOptions{Config: "english'::regconfig"})
+	})
+	require.NotNil(t, err)
+
+	err = sqlorm.DropSearchIndex(db, &Article{}, columns)
+	require.Nil(t, err)
+	require.False(t, db.Migrator().HasIndex(&Article{}, "idx_articles_title_body_fts"))
+}
@@ -0,0 +1,58 @@
+package sqlorm
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+func (repo *Repository[M]) deletedAtField() (*schema.Field, error) {
+	sch, err := repo.schema()
+	if err != nil {
+		return nil, err
+	}
+	deletedAt := reflect.TypeOf(gorm.DeletedAt{})
+	for _, field := range sch.Fields {
+		if field.FieldType == deletedAt && field.DBName != "" {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("sqlorm: %s does not support soft delete", sch.Table)
+}
+
+func (repo *Repository[M]) applyOnlyDeleted(tx *gorm.DB) *gorm.DB {
+	field, err := repo.deletedAtField()
+	if err != nil {
+		return withError(tx, err)
+	}
+	return tx.Unscoped().Where(clause.Expr{
+		SQL:  "? IS NOT NULL",
+		Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: field.DBName}},
+	})
+}
+
+// Restore undoes the soft delete of the rows matched by where.
+func (repo *Repository[M]) Restore(where interface{}) error {
+	var model M
+	field, err := repo.deletedAtField()
+	if err != nil {
+		return err
+	}
+
+	tx := repo.applyOnlyDeleted(repo.DB.Model(&model))
+	if where != nil {
+		tx = tx.Where(where)
+	}
+	result := tx.Update(field.DBName, nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (repo *Repository[M]) RestoreByID(id any) error {
+	return repo.Restore(map[string]any{"id": id})
+}
@@ -0,0 +1,78 @@
+package sqlorm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+func Test_Restore(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type Trash struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255);not null"`
+	}
+	err := db.AutoMigrate(&Trash{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[Trash]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	_, err = repo.BatchCreate([]*Trash{{Name: "a"}, {Name: "b"}, {Name: "c"}}, 5)
+	require.Nil(t, err)
+
+	err = repo.DeleteMany(map[string]any{"name": []string{"a", "b"}})
+	require.Nil(t, err)
+
+	// Only deleted rows
+	trashed, err := repo.FindAll(nil, sqlorm.FindOptions{OnlyDeleted: true, Order: []string{"name"}})
+	require.Nil(t, err)
+	require.Len(t, trashed, 2)
+	require.Equal(t, "a", trashed[0].Name)
+
+	one, err := repo.FindOne(map[string]any{"name": "c"}, sqlorm.FindOneOptions{OnlyDeleted: true})
+	require.Nil(t, err)
+	require.Nil(t, one)
+
+	count, err := repo.Count(nil, sqlorm.FindOneOptions{OnlyDeleted: true})
+	require.Nil(t, err)
+	require.Equal(t, int64(2), count)
+
+	exist, err := repo.Exist(map[string]any{"name": "b"}, sqlorm.FindOneOptions{OnlyDeleted: true})
+	require.Nil(t, err)
+	require.True(t, exist)
+
+	// Restore
+	err = repo.RestoreByID(trashed[0].ID)
+	require.Nil(t, err)
+
+	count, err = repo.Count(nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), count)
+
+	err = repo.Restore(map[string]any{"name": "b"})
+	require.Nil(t, err)
+
+	count, err = repo.Count(nil, sqlorm.FindOneOptions{OnlyDeleted: true})
+	require.Nil(t, err)
+	require.Equal(t, int64(0), count)
+
+	// Models without soft delete
+	type Permanent struct {
+		ID   uint
+		Name string
+	}
+	err = db.AutoMigrate(&Permanent{})
+	require.Nil(t, err)
+
+	permanentRepo := sqlorm.Repository[Permanent]{DB: db}
+	err = permanentRepo.RestoreByID(1)
+	require.NotNil(t, err)
+
+	_, err = permanentRepo.FindAll(nil, sqlorm.FindOptions{OnlyDeleted: true})
+	require.NotNil(t, err)
+}
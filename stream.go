@@ -0,0 +1,49 @@
+package sqlorm
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common"
+)
+
+// Stream iterates over the rows matched by where one at a time, keeping
+// memory usage constant regardless of the result size. The underlying cursor
+// is closed when the loop finishes or breaks early. Related entities are only
+// loaded through joins; Separate preloading is rejected with an error.
+func (repo *Repository[M]) Stream(where Query, options ...FindOptions) iter.Seq2[*M, error] {
+	return func(yield func(*M, error) bool) {
+		var opt FindOptions
+		if len(options) > 0 {
+			opt = common.MergeStruct(options...)
+		}
+		if opt.Separate && len(opt.Related) > 0 {
+			yield(nil, fmt.Errorf("sqlorm: Stream only supports joined relations"))
+			return
+		}
+
+		var model M
+		tx := repo.findQuery(where, options...).Model(&model)
+
+		rows, err := tx.Rows()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var record M
+			if err := tx.ScanRows(rows, &record); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(&record, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
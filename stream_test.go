@@ -0,0 +1,75 @@
+package sqlorm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"gorm.io/gorm"
+)
+
+func Test_Stream(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type StreamTodo struct {
+		gorm.Model
+		Name     string `gorm:"type:varchar(255);not null"`
+		Priority int    `gorm:"type:int"`
+	}
+	err := db.AutoMigrate(&StreamTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[StreamTodo]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	var todos []*StreamTodo
+	for i := 0; i < 50; i++ {
+		todos = append(todos, &StreamTodo{Name: fmt.Sprintf("todo %02d", i), Priority: i % 2})
+	}
+	_, err = repo.BatchCreate(todos, 20)
+	require.Nil(t, err)
+
+	var names []string
+	for todo, err := range repo.Stream(func(qb *sqlorm.QueryBuilder) {
+		qb.Equal("priority", 1)
+	}, sqlorm.FindOptions{Order: []string{"name"}}) {
+		require.Nil(t, err)
+		names = append(names, todo.Name)
+	}
+	require.Len(t, names, 25)
+	require.Equal(t, "todo 01", names[0])
+	require.Equal(t, "todo 49", names[24])
+
+	// Break early closes the cursor and releases the connection
+	for i := 0; i < 20; i++ {
+		count := 0
+		for _, err := range repo.Stream(nil) {
+			require.Nil(t, err)
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		require.Equal(t, 3, count)
+	}
+	sqlDB, err := db.DB()
+	require.Nil(t, err)
+	require.Equal(t, 0, sqlDB.Stats().InUse)
+
+	// Errors are yielded
+	for todo, err := range repo.Stream(map[string]any{"unknown_column": 1}) {
+		require.Nil(t, todo)
+		require.NotNil(t, err)
+	}
+
+	// Separate preloading would be silently skipped
+	calls := 0
+	for todo, err := range repo.Stream(nil, sqlorm.FindOptions{Related: []string{"Owner"}, Separate: true}) {
+		calls++
+		require.Nil(t, todo)
+		require.ErrorContains(t, err, "joined relations")
+	}
+	require.Equal(t, 1, calls)
+}
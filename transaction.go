@@ -0,0 +1,86 @@
+package sqlorm
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+	"gorm.io/gorm"
+)
+
+const UnitOfWorkProvider core.Provide = "UnitOfWork"
+
+// Transaction runs fc inside a database transaction with a repository bound
+// to it. The transaction is committed when fc returns nil and rolled back when
+// it returns an error or panics.
+func (repo *Repository[M]) Transaction(fc func(tx *Repository[M]) error, opts ...*sql.TxOptions) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		return fc(repo.clone(tx))
+	}, opts...)
+}
+
+func (repo *Repository[M]) clone(db *gorm.DB) *Repository[M] {
+	cloned := *repo
+	cloned.DB = db
+	return &cloned
+}
+
+// UnitOfWork groups operations of several repositories in one transaction.
+// Repositories registered via ForFeature are available inside Do through
+// TxRepository.
+type UnitOfWork struct {
+	DB    *gorm.DB
+	mu    sync.RWMutex
+	repos map[core.Provide]RepoCommon
+}
+
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{
+		DB:    db,
+		repos: make(map[core.Provide]RepoCommon),
+	}
+}
+
+func (u *UnitOfWork) Register(repos ...RepoCommon) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, repo := range repos {
+		u.repos[GetRepoName(repo.GetName())] = repo
+	}
+}
+
+func (u *UnitOfWork) Do(fc func(tx *Tx) error, opts ...*sql.TxOptions) error {
+	return u.DB.Transaction(func(db *gorm.DB) error {
+		return fc(&Tx{DB: db, uow: u})
+	}, opts...)
+}
+
+type Tx struct {
+	DB  *gorm.DB
+	uow *UnitOfWork
+}
+
+// TxRepository returns a clone of the registered repository for M bound to
+// the transaction, or nil when no repository for M was registered.
+func TxRepository[M any](tx *Tx) *Repository[M] {
+	if tx == nil || tx.uow == nil {
+		return nil
+	}
+	var repo Repository[M]
+
+	tx.uow.mu.RLock()
+	registered, ok := tx.uow.repos[GetRepoName(repo.GetName())].(*Repository[M])
+	tx.uow.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return registered.clone(tx.DB)
+}
+
+func InjectUnitOfWork(ref core.RefProvider) *UnitOfWork {
+	uow, ok := ref.Ref(UnitOfWorkProvider).(*UnitOfWork)
+	if !ok {
+		return nil
+	}
+	return uow
+}
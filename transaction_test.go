@@ -0,0 +1,183 @@
+package sqlorm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/sqlorm/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_Transaction(t *testing.T) {
+	db := prepareBeforeTest(t)
+
+	type TxTodo struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(255);not null"`
+	}
+	err := db.AutoMigrate(&TxTodo{})
+	require.Nil(t, err)
+
+	repo := sqlorm.Repository[TxTodo]{DB: db}
+	err = repo.DeleteMany(nil, true)
+	require.Nil(t, err)
+
+	// Commit
+	err = repo.Transaction(func(tx *sqlorm.Repository[TxTodo]) error {
+		_, err := tx.Create(&TxTodo{Name: "commit"})
+		if err != nil {
+			return err
+		}
+		_, err = tx.UpdateOne(map[string]any{"name": "commit"}, &TxTodo{Name: "committed"})
+		if err != nil {
+			return err
+		}
+		todos, total, err := tx.FindAllAndCount(nil)
+		if err != nil {
+			return err
+		}
+		require.Len(t, todos, 1)
+		require.Equal(t, int64(1), total)
+		return nil
+	})
+	require.Nil(t, err)
+
+	exist, err := repo.Exist(map[string]any{"name": "committed"})
+	require.Nil(t, err)
+	require.True(t, exist)
+
+	// Rollback on error
+	err = repo.Transaction(func(tx *sqlorm.Repository[TxTodo]) error {
+		_, err := tx.Create(&TxTodo{Name: "rollback"})
+		if err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	require.NotNil(t, err)
+
+	exist, err = repo.Exist(map[string]any{"name": "rollback"})
+	require.Nil(t, err)
+	require.False(t, exist)
+
+	// Rollback on panic
+	require.Panics(t, func() {
+		_ = repo.Transaction(func(tx *sqlorm.Repository[TxTodo]) error {
+			_, err := tx.Create(&TxTodo{Name: "panic"})
+			require.Nil(t, err)
+			panic("abort")
+		})
+	})
+
+	exist, err = repo.Exist(map[string]any{"name": "panic"})
+	require.Nil(t, err)
+	require.False(t, exist)
+}
+
+type Account struct {
+	gorm.Model
+	Name    string `gorm:"type:varchar(255);not null"`
+	Balance int    `gorm:"type:int;not null;default:0"`
+}
+
+type Ledger struct {
+	gorm.Model
+	AccountID uint
+	Amount    int `gorm:"type:int;not null"`
+}
+
+func Test_UnitOfWork(t *testing.T) {
+	require.NotPanics(t, func() {
+		createDatabaseForTest("test")
+	})
+	dsn := "host=localhost user=postgres password=postgres dbname=test port=5432 sslmode=disable TimeZone=Asia/Shanghai"
+
+	appModule := core.NewModule(core.NewModuleOptions{
+		Imports: []core.Modules{
+			sqlorm.ForRoot(sqlorm.Config{
+				Dialect: postgres.Open(dsn),
+				Models:  []interface{}{&Account{}, &Ledger{}},
+				Sync:    true,
+			}),
+			sqlorm.ForFeature(sqlorm.NewRepo(Account{}), sqlorm.NewRepo(Ledger{})),
+		},
+	})
+
+	uow := sqlorm.InjectUnitOfWork(appModule)
+	require.NotNil(t, uow)
+
+	accountRepo := sqlorm.InjectRepository[Account](appModule)
+	require.NotNil(t, accountRepo)
+	ledgerRepo := sqlorm.InjectRepository[Ledger](appModule)
+	require.NotNil(t, ledgerRepo)
+
+	account, err := accountRepo.Create(&Account{Name: "uow", Balance: 100})
+	require.Nil(t, err)
+
+	// Commit
+	err = uow.Do(func(tx *sqlorm.Tx) error {
+		accounts := sqlorm.TxRepository[Account](tx)
+		ledgers := sqlorm.TxRepository[Ledger](tx)
+		require.NotNil(t, accounts)
+		require.NotNil(t, ledgers)
+
+		if err := accounts.Decrement(account.ID, "balance", 30); err != nil {
+			return err
+		}
+		if _, err := ledgers.Create(&Ledger{AccountID: account.ID, Amount: -30}); err != nil {
+			return err
+		}
+		entries, total, err := ledgers.FindAllAndCount(map[string]any{"account_id": account.ID})
+		if err != nil {
+			return err
+		}
+		require.Len(t, entries, 1)
+		require.Equal(t, int64(1), total)
+		return nil
+	})
+	require.Nil(t, err)
+
+	found, err := accountRepo.FindByID(account.ID)
+	require.Nil(t, err)
+	require.Equal(t, 70, found.Balance)
+
+	count, err := ledgerRepo.Count(map[string]any{"account_id": account.ID})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Rollback
+	err = uow.Do(func(tx *sqlorm.Tx) error {
+		accounts := sqlorm.TxRepository[Account](tx)
+		ledgers := sqlorm.TxRepository[Ledger](tx)
+
+		if err := accounts.Decrement(account.ID, "balance", 100); err != nil {
+			return err
+		}
+		if _, err := ledgers.Create(&Ledger{AccountID: account.ID, Amount: -100}); err != nil {
+			return err
+		}
+		return errors.New("insufficient balance")
+	})
+	require.NotNil(t, err)
+
+	found, err = accountRepo.FindByID(account.ID)
+	require.Nil(t, err)
+	require.Equal(t, 70, found.Balance)
+
+	count, err = ledgerRepo.Count(map[string]any{"account_id": account.ID})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Unregistered repository
+	type Unknown struct {
+		gorm.Model
+	}
+	err = uow.Do(func(tx *sqlorm.Tx) error {
+		require.Nil(t, sqlorm.TxRepository[Unknown](tx))
+		return nil
+	})
+	require.Nil(t, err)
+}
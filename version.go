@@ -0,0 +1,150 @@
+package sqlorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	ErrStaleObject    = errors.New("sqlorm: stale object")
+	ErrMissingVersion = errors.New("sqlorm: update input has no version")
+)
+
+// Versioned enables optimistic locking when embedded in a model. Any other
+// integer field can opt in with the `sqlorm:"version"` tag instead.
+type Versioned struct {
+	Version int64 `gorm:"not null;default:1" sqlorm:"version" json:"version"`
+}
+
+func versionField(sch *schema.Schema) *schema.Field {
+	for _, field := range sch.Fields {
+		if field.Tag.Get("sqlorm") == "version" && field.DBName != "" {
+			return field
+		}
+	}
+	return nil
+}
+
+func (repo *Repository[M]) versionField() *schema.Field {
+	sch, err := repo.schema()
+	if err != nil {
+		return nil
+	}
+	return versionField(sch)
+}
+
+// lockVersion returns the condition matching the version carried by val, the
+// value passed to the update, and stores the next version in input so the
+// update persists it. The version is looked up in val itself, since MapOne
+// only copies the top level fields of a DTO or map.
+func (repo *Repository[M]) lockVersion(field *schema.Field, val interface{}, input *M) (clause.Expression, error) {
+	ctx := repo.DB.Statement.Context
+	rv := reflect.ValueOf(input).Elem()
+
+	current, ok := inputVersion(ctx, field, val)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is missing", ErrMissingVersion, field.Name)
+	}
+	if err := field.Set(ctx, rv, current); err != nil {
+		return nil, err
+	}
+	current, _ = field.ValueOf(ctx, rv)
+	fv := field.ReflectValueOf(ctx, rv)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fv.SetInt(fv.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fv.SetUint(fv.Uint() + 1)
+	}
+	return clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+		Value:  current,
+	}, nil
+}
+
+// inputVersion reads the version from a model, a DTO or a map, matching the
+// field name or the column name of the version field. A zero version counts
+// as missing, since versions start at 1.
+func inputVersion(ctx context.Context, field *schema.Field, val interface{}) (interface{}, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(val))
+	var value reflect.Value
+	switch {
+	case !rv.IsValid():
+		return nil, false
+	case rv.Type() == field.Schema.ModelType:
+		value = field.ReflectValueOf(ctx, rv)
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		for _, key := range []string{field.Name, field.DBName} {
+			if v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())); v.IsValid() {
+				value = v
+				break
+			}
+		}
+	case rv.Kind() == reflect.Struct:
+		value = rv.FieldByNameFunc(func(name string) bool {
+			return name == field.Name || strings.EqualFold(name, field.DBName)
+		})
+	}
+
+	for value.IsValid() && (value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr) {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() || value.IsZero() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+// bumpVersion returns the columns Updates would write for input with the
+// version incremented in the same statement, for updates that are not
+// checked against the version of one row.
+func (repo *Repository[M]) bumpVersion(field *schema.Field, input *M) map[string]interface{} {
+	ctx := repo.DB.Statement.Context
+	rv := reflect.ValueOf(input).Elem()
+	values := map[string]interface{}{field.DBName: versionIncrement(field)}
+
+	sch, err := repo.schema()
+	if err != nil {
+		return values
+	}
+	for _, f := range sch.Fields {
+		if f.DBName == "" || !f.Updatable || f.PrimaryKey || f == field {
+			continue
+		}
+		if value, zero := f.ValueOf(ctx, rv); !zero {
+			values[f.DBName] = value
+		}
+	}
+	return values
+}
+
+func versionIncrement(field *schema.Field) clause.Expr {
+	return gorm.Expr("? + 1", clause.Column{Name: field.DBName})
+}
+
+func (repo *Repository[M]) initVersion(field *schema.Field, inputs ...*M) {
+	ctx := repo.DB.Statement.Context
+	for _, input := range inputs {
+		rv := reflect.ValueOf(input).Elem()
+		if _, zero := field.ValueOf(ctx, rv); !zero {
+			continue
+		}
+		fv := field.ReflectValueOf(ctx, rv)
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fv.SetInt(1)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fv.SetUint(1)
+		}
+	}
+}
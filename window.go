@@ -0,0 +1,82 @@
+package sqlorm
+
+import "slices"
+
+const windowTotalColumn = "sqlorm_total"
+
+// findAllAndCountOver reads the rows and the total with COUNT(*) OVER(), so
+// both come from the same snapshot. Distinct, Lock and Separate relations
+// cannot be combined with a window function and use two queries instead, as
+// does a page past the end, which has no row to carry the total.
+func (repo *Repository[M]) findAllAndCountOver(where Query, opt FindOptions) ([]*M, int64, error) {
+	opt.WindowCount = false
+	if len(opt.Distinct) > 0 || opt.Lock != nil || (opt.Separate && len(opt.Related) > 0) {
+		return repo.FindAllAndCount(where, opt)
+	}
+
+	var model M
+	tx := repo.findQuery(where, opt).Model(&model)
+	total := "COUNT(*) OVER() AS " + windowTotalColumn
+	if _, ok := tx.Statement.Clauses["SELECT"]; ok {
+		qb := &QueryBuilder{qb: tx}
+		qb.addSelect(total)
+		tx = qb.qb
+	} else {
+		// Selecting through Statement.Selects keeps the columns gorm adds
+		// for joined relations.
+		selects := slices.Clone(tx.Statement.Selects)
+		if len(selects) == 0 {
+			sch, err := repo.schema()
+			if err != nil {
+				return nil, 0, err
+			}
+			selects = []string{tx.Statement.Quote(sch.Table) + ".*"}
+		}
+		tx = tx.Select(append(selects, total))
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+	var count int64
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		if column == windowTotalColumn {
+			values[i] = &count
+		} else {
+			values[i] = new(interface{})
+		}
+	}
+
+	records := []*M{}
+	for rows.Next() {
+		var record M
+		if err := tx.ScanRows(rows, &record); err != nil {
+			return nil, 0, err
+		}
+		if len(records) == 0 {
+			if err := rows.Scan(values...); err != nil {
+				return nil, 0, err
+			}
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(records) == 0 && opt.Offset > 0 {
+		count, err = repo.countAll(where, opt)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return records, count, nil
+}